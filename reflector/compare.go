@@ -0,0 +1,308 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// methodSignature returns the method's func type without the receiver.
+func methodSignature(method reflect.Method) reflect.Type {
+	ty := method.Type
+	in := make([]reflect.Type, 0, ty.NumIn())
+	for i := 1; i < ty.NumIn(); i++ {
+		in = append(in, ty.In(i))
+	}
+	out := make([]reflect.Type, 0, ty.NumOut())
+	for i := 0; i < ty.NumOut(); i++ {
+		out = append(out, ty.Out(i))
+	}
+	return reflect.FuncOf(in, out, ty.IsVariadic())
+}
+
+// MethodSetDiff compares the method sets of two objects.
+// Added are methods existing only in b, removed are methods existing only in a and changed are
+// methods existing in both but with different signatures.
+func MethodSetDiff(a, b *Obj) (added, removed, changed []string) {
+	for _, name := range a.methodNames {
+		methodB, found := b.methods[name]
+		if !found {
+			removed = append(removed, name)
+			continue
+		}
+		if methodSignature(a.methods[name].method) != methodSignature(methodB.method) {
+			changed = append(changed, name)
+		}
+	}
+	for _, name := range b.methodNames {
+		if _, found := a.methods[name]; !found {
+			added = append(added, name)
+		}
+	}
+	return
+}
+
+// FieldLayoutDiff compares flattened fields (see FieldsFlattened) of two objects.
+// Added are fields existing only in b, removed are fields existing only in a and typeChanged are
+// fields existing in both but with different types.
+func FieldLayoutDiff(a, b *Obj) (added, removed, typeChanged []string) {
+	namesA, namesB := flattenedNameSet(a), flattenedNameSet(b)
+	for _, name := range uniqueNames(a.fieldNamesFlattenAnonymous) {
+		if !namesB[name] {
+			removed = append(removed, name)
+		} else if a.fields[name].fieldType != b.fields[name].fieldType {
+			typeChanged = append(typeChanged, name)
+		}
+	}
+	for _, name := range uniqueNames(b.fieldNamesFlattenAnonymous) {
+		if !namesA[name] {
+			added = append(added, name)
+		}
+	}
+	return
+}
+
+func flattenedNameSet(o *Obj) map[string]bool {
+	res := map[string]bool{}
+	for _, name := range o.fieldNamesFlattenAnonymous {
+		res[name] = true
+	}
+	return res
+}
+
+// uniqueNames returns names without duplicates, in the original order.
+func uniqueNames(names []string) []string {
+	res := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// FieldsDifferingFrom returns exported flattened fields with values different from the same fields in template.
+// Both objects must be of the same struct type (pointer or not). Fields declared in nil embedded pointers are
+// zero values in the template, and are skipped in this object.
+func (o *Obj) FieldsDifferingFrom(template *Obj) ([]ObjField, error) {
+	if !o.IsStructOrPtrToStruct() || o.underlyingType != template.underlyingType {
+		return nil, fmt.Errorf("cannot compare %s with %s", o.String(), template.String())
+	}
+	if !o.fieldsValue.IsValid() || !template.fieldsValue.IsValid() {
+		return nil, fmt.Errorf("cannot compare nil values")
+	}
+
+	var res []ObjField
+	for _, field := range o.FieldsFlattened() {
+		if !field.IsExported() || !field.IsValid() {
+			continue
+		}
+		templateValue := reflect.Zero(field.fieldType)
+		if templateField := template.Field(field.name); templateField.IsValid() && templateField.value.IsValid() {
+			templateValue = templateField.value
+		}
+		if !reflect.DeepEqual(field.value.Interface(), templateValue.Interface()) {
+			res = append(res, field)
+		}
+	}
+	return res, nil
+}
+
+// FieldDiff is a difference between two values at the given (dotted) field path.
+type FieldDiff struct {
+	// Path is the dotted field path, for example "Address.Street" (empty for the root value).
+	Path  string
+	Left  interface{}
+	Right interface{}
+}
+
+// visitedPointers is a pair of pointers already being compared (see reflect.DeepEqual).
+type visitedPointers struct {
+	a, b uintptr
+	ty   reflect.Type
+}
+
+// diffValues appends differences between a and b. Pointer pairs already in visited are considered equal, so
+// cyclic values don't recurse forever.
+func diffValues(path string, a, b reflect.Value, res []FieldDiff, visited map[visitedPointers]bool) []FieldDiff {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			res = append(res, FieldDiff{Path: path, Left: valueInterface(a), Right: valueInterface(b)})
+		}
+		return res
+	}
+	if a.Type() != b.Type() {
+		return append(res, FieldDiff{Path: path, Left: a.Interface(), Right: b.Interface()})
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				res = append(res, FieldDiff{Path: path, Left: a.Interface(), Right: b.Interface()})
+			}
+			return res
+		}
+		if a.Elem().Kind() == reflect.Struct {
+			key := visitedPointers{a: a.Pointer(), b: b.Pointer(), ty: a.Type()}
+			if visited[key] {
+				return res
+			}
+			visited[key] = true
+			return diffValues(path, a.Elem(), b.Elem(), res, visited)
+		}
+	case reflect.Struct:
+		if hasExportedFields(a.Type()) || hasUnexportedEmbeds(a.Type()) {
+			return diffStructFields(path, a, b, res, visited)
+		}
+	}
+
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		res = append(res, FieldDiff{Path: path, Left: a.Interface(), Right: b.Interface()})
+	}
+	return res
+}
+
+// diffStructFields appends differences between exported fields of two structs of the same type, including
+// exported fields of unexported embedded structs.
+func diffStructFields(path string, a, b reflect.Value, res []FieldDiff, visited map[visitedPointers]bool) []FieldDiff {
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		fieldPath := joinPath(path, field.Name)
+		if isUnexportedEmbed(field) {
+			// Can't be compared as a value, but its exported fields are promoted. A nil pointer is compared as a
+			// zero struct:
+			fa, fb := a.Field(i), b.Field(i)
+			if fa.Kind() == reflect.Ptr {
+				if !fa.IsNil() && !fb.IsNil() {
+					key := visitedPointers{a: fa.Pointer(), b: fb.Pointer(), ty: fa.Type()}
+					if visited[key] {
+						continue
+					}
+					visited[key] = true
+				}
+				fa, fb = embeddedStruct(fa), embeddedStruct(fb)
+			}
+			res = diffStructFields(fieldPath, fa, fb, res, visited)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		res = diffValues(fieldPath, a.Field(i), b.Field(i), res, visited)
+	}
+	return res
+}
+
+// isUnexportedEmbed returns true for unexported embedded structs (or pointers to structs). Their exported fields
+// are still promoted (see FieldsFlattened).
+func isUnexportedEmbed(field reflect.StructField) bool {
+	return field.PkgPath != "" && field.Anonymous && isStructOrPtrToStruct(field.Type)
+}
+
+func hasUnexportedEmbeds(ty reflect.Type) bool {
+	for i := 0; i < ty.NumField(); i++ {
+		if isUnexportedEmbed(ty.Field(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// embeddedStruct dereferences an embedded pointer to struct, a nil pointer is a zero struct.
+func embeddedStruct(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Ptr {
+		return v
+	}
+	if v.IsNil() {
+		return reflect.Zero(v.Type().Elem())
+	}
+	return v.Elem()
+}
+
+func valueInterface(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func hasExportedFields(ty reflect.Type) bool {
+	for i := 0; i < ty.NumField(); i++ {
+		if ty.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Diff returns differences between two objects. Pointers are dereferenced, nested and embedded structs are
+// compared field by field (exported fields only, including those promoted from unexported embedded structs), other
+// values with reflect.DeepEqual. Objects of different types result in a single difference with an empty path.
+func (o *Obj) Diff(other *Obj) ([]FieldDiff, error) {
+	if !o.IsValid() || !other.IsValid() {
+		return nil, fmt.Errorf("cannot compare %s with %s", o.String(), other.String())
+	}
+	defer o.rlock()()
+	if other.mu != o.mu {
+		defer other.rlock()()
+	}
+	visited := map[visitedPointers]bool{}
+	a, b := o.fieldsValue, other.fieldsValue
+	if a.CanAddr() && b.CanAddr() && a.Type() == b.Type() {
+		// The root values may be pointed to from inside (cycles):
+		visited[visitedPointers{a: a.Addr().Pointer(), b: b.Addr().Pointer(), ty: reflect.PtrTo(a.Type())}] = true
+	}
+	return diffValues("", a, b, []FieldDiff{}, visited), nil
+}
+
+// DeepEqual checks if there are no differences between the objects (see Diff). Two nil objects are equal.
+func (o *Obj) DeepEqual(other *Obj) bool {
+	if !o.IsValid() || !other.IsValid() {
+		return o.IsValid() == other.IsValid()
+	}
+	diffs, err := o.Diff(other)
+	return err == nil && len(diffs) == 0
+}
+
+// DiffReport returns a human readable report of differences between two objects, one line per differing field:
+//
+//	Address.Street: "Main" -> "Other"
+//
+// Nested and embedded structs are compared field by field (exported fields only), an empty string means
+// that no differences were found.
+func (o *Obj) DiffReport(other *Obj) (string, error) {
+	diffs, err := o.Diff(other)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, diff := range diffs {
+		path := diff.Path
+		if path == "" {
+			path = "(root)"
+		}
+		fmt.Fprintf(&sb, "%s: %s -> %s\n", path, formatDiffValue(diff.Left), formatDiffValue(diff.Right))
+	}
+	return sb.String(), nil
+}
+
+func formatDiffValue(value interface{}) string {
+	if isNil(value) {
+		return "nil"
+	}
+	if str, is := value.(string); is {
+		return strconv.Quote(str)
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,245 @@
+package reflector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type APIv1 struct {
+	ID   int
+	Name string
+	Tags []string
+}
+
+func (APIv1) Get(id int) string        { return "" }
+func (APIv1) List() []string           { return nil }
+func (APIv1) Delete(id int) error      { return nil }
+func (*APIv1) Update(name string) bool { return true }
+
+type APIv2 struct {
+	ID    int64
+	Name  string
+	Email string
+}
+
+func (APIv2) Get(id int) string          { return "" }
+func (APIv2) List(limit int) []string    { return nil }
+func (APIv2) Create(name string) error   { return nil }
+func (*APIv2) Update(name string) bool   { return true }
+func (APIv2) Search(q ...string) []APIv2 { return nil }
+
+func TestMethodSetDiff(t *testing.T) {
+	t.Parallel()
+	added, removed, changed := MethodSetDiff(New(APIv1{}), New(APIv2{}))
+	assert.Equal(t, []string{"Create", "Search"}, added)
+	assert.Equal(t, []string{"Delete"}, removed)
+	assert.Equal(t, []string{"List"}, changed)
+
+	// Pointer method sets include Update on both sides, with the same signature:
+	added, removed, changed = MethodSetDiff(New(&APIv1{}), New(&APIv2{}))
+	assert.Equal(t, []string{"Create", "Search"}, added)
+	assert.Equal(t, []string{"Delete"}, removed)
+	assert.Equal(t, []string{"List"}, changed)
+
+	added, removed, changed = MethodSetDiff(New(APIv1{}), New(APIv1{}))
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+	assert.Empty(t, changed)
+
+	added, removed, _ = MethodSetDiff(New(nil), New(&APIv1{}))
+	assert.Equal(t, []string{"Delete", "Get", "List", "Update"}, added)
+	assert.Empty(t, removed)
+}
+
+func TestFieldLayoutDiff(t *testing.T) {
+	t.Parallel()
+	added, removed, typeChanged := FieldLayoutDiff(New(APIv1{}), New(&APIv2{}))
+	assert.Equal(t, []string{"Email"}, added)
+	assert.Equal(t, []string{"Tags"}, removed)
+	assert.Equal(t, []string{"ID"}, typeChanged)
+
+	// Embedded fields are flattened:
+	added, removed, typeChanged = FieldLayoutDiff(New(Address{}), New(Person{}))
+	assert.Equal(t, []string{"Name"}, added)
+	assert.Empty(t, removed)
+	assert.Empty(t, typeChanged)
+
+	// A named field replaced with an embedded one (and back):
+	type Named struct{ Address Address }
+	type Embedded struct{ Address }
+	added, removed, typeChanged = FieldLayoutDiff(New(Named{}), New(Embedded{}))
+	assert.Equal(t, []string{"Street", "Number"}, added)
+	assert.Equal(t, []string{"Address"}, removed)
+	assert.Empty(t, typeChanged)
+	added, removed, typeChanged = FieldLayoutDiff(New(Embedded{}), New(Named{}))
+	assert.Equal(t, []string{"Address"}, added)
+	assert.Equal(t, []string{"Street", "Number"}, removed)
+	assert.Empty(t, typeChanged)
+
+	added, removed, typeChanged = FieldLayoutDiff(New(Person{}), New(Person{}))
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+	assert.Empty(t, typeChanged)
+}
+
+func TestFieldsDifferingFrom(t *testing.T) {
+	t.Parallel()
+	defaults := Person{Name: "Default", Address: Address{Number: 1}}
+	p := Person{Name: "Default", Address: Address{Street: "Main", Number: 2}}
+
+	fields, err := New(&p).FieldsDifferingFrom(New(defaults))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fields))
+	assert.Equal(t, "Street", fields[0].Name())
+	assert.Equal(t, "Number", fields[1].Name())
+	value, err := fields[1].Get()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, value)
+
+	fields, err = New(defaults).FieldsDifferingFrom(New(&defaults))
+	assert.Nil(t, err)
+	assert.Empty(t, fields)
+
+	// Nil embedded pointer in the template:
+	fields, err = New(WithAddressPtr{Address: &Address{Street: "Main"}}).FieldsDifferingFrom(New(WithAddressPtr{}))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fields))
+	assert.Equal(t, "Street", fields[0].Name())
+	fields, err = New(WithAddressPtr{}).FieldsDifferingFrom(New(WithAddressPtr{Address: &Address{Street: "Main"}}))
+	assert.Nil(t, err)
+	assert.Empty(t, fields)
+
+	_, err = New(p).FieldsDifferingFrom(New(Address{}))
+	assert.NotNil(t, err)
+	_, err = New(p).FieldsDifferingFrom(New((*Person)(nil)))
+	assert.NotNil(t, err)
+	_, err = New(1).FieldsDifferingFrom(New(1))
+	assert.NotNil(t, err)
+}
+
+func TestDiffReport(t *testing.T) {
+	t.Parallel()
+	a := Shape{Name: "a", Center: Point{X: 1, Y: 2}, Person: Person{Address: Address{Street: "Main"}}}
+	b := Shape{Name: "b", Center: Point{X: 1, Y: 3}, Corner: &Point{}, Person: Person{Address: Address{Street: "Other"}}}
+
+	report, err := New(a).DiffReport(New(&b))
+	assert.Nil(t, err)
+	assert.Equal(t, `Name: "a" -> "b"
+Center.Y: 2 -> 3
+Corner: nil -> &{0 0}
+Person.Address.Street: "Main" -> "Other"
+`, report)
+
+	report, err = New(a).DiffReport(New(a))
+	assert.Nil(t, err)
+	assert.Equal(t, "", report)
+
+	report, err = New(1).DiffReport(New("1"))
+	assert.Nil(t, err)
+	assert.Equal(t, "(root): 1 -> \"1\"\n", report)
+
+	_, err = New(nil).DiffReport(New(a))
+	assert.NotNil(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	a := Person{Name: "John", Address: Address{Street: "Main", Number: 1}}
+	b := Person{Name: "John", Address: Address{Street: "Other", Number: 2}}
+
+	diffs, err := New(a).Diff(New(&b))
+	assert.Nil(t, err)
+	assert.Equal(t, []FieldDiff{
+		{Path: "Address.Street", Left: "Main", Right: "Other"},
+		{Path: "Address.Number", Left: 1, Right: 2},
+	}, diffs)
+	assert.False(t, New(a).DeepEqual(New(&b)))
+
+	diffs, err = New(a).Diff(New(a))
+	assert.Nil(t, err)
+	assert.Empty(t, diffs)
+	assert.True(t, New(a).DeepEqual(New(&a)))
+
+	// Different types:
+	diffs, err = New(a).Diff(New(b.Address))
+	assert.Nil(t, err)
+	assert.Equal(t, []FieldDiff{{Path: "", Left: a, Right: b.Address}}, diffs)
+	assert.False(t, New(a).DeepEqual(New(1)))
+
+	_, err = New(nil).Diff(New(a))
+	assert.NotNil(t, err)
+	assert.True(t, New(nil).DeepEqual(New(nil)))
+	assert.False(t, New(nil).DeepEqual(New(a)))
+
+	obj := New(&a).WithMutex()
+	assert.True(t, obj.DeepEqual(obj))
+}
+
+type hiddenBase struct {
+	Label string
+	Count int
+}
+
+type hiddenExtra struct {
+	Note string
+}
+
+type WithHiddenBase struct {
+	Name string
+	hiddenBase
+	*hiddenExtra
+}
+
+func TestDiffUnexportedEmbedded(t *testing.T) {
+	t.Parallel()
+	a := WithHiddenBase{Name: "a", hiddenBase: hiddenBase{Label: "x", Count: 1}}
+	b := WithHiddenBase{Name: "a", hiddenBase: hiddenBase{Label: "y", Count: 1}, hiddenExtra: &hiddenExtra{Note: "n"}}
+
+	diffs, err := New(a).Diff(New(&b))
+	assert.Nil(t, err)
+	assert.Equal(t, []FieldDiff{
+		{Path: "hiddenBase.Label", Left: "x", Right: "y"},
+		{Path: "hiddenExtra.Note", Left: "", Right: "n"},
+	}, diffs)
+	assert.False(t, New(a).DeepEqual(New(b)))
+
+	b = WithHiddenBase{Name: "a", hiddenBase: hiddenBase{Label: "x", Count: 1}, hiddenExtra: &hiddenExtra{}}
+	assert.True(t, New(a).DeepEqual(New(b)))
+}
+
+type CycleNode struct {
+	Name string
+	Next *CycleNode
+}
+
+func TestDeepEqualCyclic(t *testing.T) {
+	t.Parallel()
+	a := &CycleNode{Name: "a"}
+	a.Next = a
+	b := &CycleNode{Name: "a"}
+	b.Next = b
+	assert.True(t, New(a).DeepEqual(New(b)))
+
+	c := &CycleNode{Name: "c"}
+	c.Next = c
+	diffs, err := New(a).Diff(New(c))
+	assert.Nil(t, err)
+	assert.Equal(t, []FieldDiff{{Path: "Name", Left: "a", Right: "c"}}, diffs)
+}
+
+func TestDiffReportCyclic(t *testing.T) {
+	t.Parallel()
+	a := &CycleNode{Name: "a", Next: &CycleNode{Name: "b"}}
+	a.Next.Next = a
+	c := &CycleNode{Name: "a", Next: &CycleNode{Name: "c"}}
+	c.Next.Next = c
+
+	report, err := New(a).DiffReport(New(c))
+	assert.Nil(t, err)
+	assert.Equal(t, "Next.Name: \"b\" -> \"c\"\n", report)
+
+	report, err = New(a).DiffReport(New(a))
+	assert.Nil(t, err)
+	assert.Equal(t, "", report)
+}
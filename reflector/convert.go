@@ -0,0 +1,226 @@
+package reflector
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// SetConvert sets the field like Set, but converts values which are not assignable to the field type: numbers
+// (only when no precision is lost, for example float64 17 from JSON to an int field), string <-> []byte, custom
+// types with the same kind, strings parsed into numbers and bools, and slices and maps element by element.
+func (of *ObjField) SetConvert(value interface{}) error {
+	if err := of.assertValid(); err != nil {
+		return err
+	}
+	if !of.IsSettable() {
+		return fmt.Errorf("field %s in %T not settable", of.name, of.obj.iface)
+	}
+
+	v, err := convertValue(value, of.fieldType)
+	if err != nil {
+		return fmt.Errorf("field %s in %T: %w", of.name, of.obj.iface, err)
+	}
+
+	defer of.obj.lock()()
+	of.value.Set(v)
+	return nil
+}
+
+// convertValue converts the value to type ty. Assignable values are used as they are, numbers are converted
+// between numeric kinds only if no precision is lost, strings are parsed into numbers and bools, slices
+// and maps are converted element by element, and values of the same kind are converted if reflect allows it
+// (for example string to a custom string type).
+func convertValue(value interface{}, ty reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return assignableValue(value, ty)
+	}
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(ty) {
+		return v, nil
+	}
+
+	switch {
+	case isNumberKind(v.Kind()) && isNumberKind(ty.Kind()):
+		return convertNumber(v, ty)
+	case v.Kind() == ty.Kind() && v.Type().ConvertibleTo(ty):
+		return v.Convert(ty), nil
+	case isBytes(v.Type()) && ty.Kind() == reflect.String, v.Kind() == reflect.String && isBytes(ty):
+		return v.Convert(ty), nil
+	case v.Kind() == reflect.String && (isNumberKind(ty.Kind()) || ty.Kind() == reflect.Bool):
+		return parseString(v.String(), ty)
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && ty.Kind() == reflect.Slice:
+		return convertSlice(v, ty)
+	case v.Kind() == reflect.Map && ty.Kind() == reflect.Map:
+		return convertMap(v, ty)
+	}
+	return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", v.Type().String(), ty.String())
+}
+
+func isBytes(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Slice && ty.Elem().Kind() == reflect.Uint8
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return isIntKind(kind) || isUintKind(kind) || isFloatKind(kind)
+}
+
+func convertNumber(v reflect.Value, ty reflect.Type) (reflect.Value, error) {
+	res := reflect.New(ty).Elem()
+	overflow := fmt.Errorf("value %v overflows %s", v.Interface(), ty.String())
+	switch {
+	case isIntKind(ty.Kind()):
+		var i int64
+		switch {
+		case isIntKind(v.Kind()):
+			i = v.Int()
+		case isUintKind(v.Kind()):
+			if v.Uint() > math.MaxInt64 {
+				return reflect.Value{}, overflow
+			}
+			i = int64(v.Uint())
+		default:
+			f := v.Float()
+			if f != math.Trunc(f) {
+				return reflect.Value{}, fmt.Errorf("value %v is not an integer", f)
+			}
+			if f < math.MinInt64 || f >= math.MaxInt64 {
+				return reflect.Value{}, overflow
+			}
+			i = int64(f)
+		}
+		if res.OverflowInt(i) {
+			return reflect.Value{}, overflow
+		}
+		res.SetInt(i)
+	case isUintKind(ty.Kind()):
+		var u uint64
+		switch {
+		case isIntKind(v.Kind()):
+			if v.Int() < 0 {
+				return reflect.Value{}, overflow
+			}
+			u = uint64(v.Int())
+		case isUintKind(v.Kind()):
+			u = v.Uint()
+		default:
+			f := v.Float()
+			if f != math.Trunc(f) {
+				return reflect.Value{}, fmt.Errorf("value %v is not an integer", f)
+			}
+			if f < 0 || f >= math.MaxUint64 {
+				return reflect.Value{}, overflow
+			}
+			u = uint64(f)
+		}
+		if res.OverflowUint(u) {
+			return reflect.Value{}, overflow
+		}
+		res.SetUint(u)
+	default:
+		var f float64
+		switch {
+		case isIntKind(v.Kind()):
+			f = float64(v.Int())
+		case isUintKind(v.Kind()):
+			f = float64(v.Uint())
+		default:
+			f = v.Float()
+		}
+		if res.OverflowFloat(f) {
+			return reflect.Value{}, overflow
+		}
+		res.SetFloat(f)
+	}
+	return res, nil
+}
+
+func convertSlice(v reflect.Value, ty reflect.Type) (reflect.Value, error) {
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.Zero(ty), nil
+	}
+	res := reflect.MakeSlice(ty, v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem, err := convertValue(v.Index(i).Interface(), ty.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("index %d: %w", i, err)
+		}
+		res.Index(i).Set(elem)
+	}
+	return res, nil
+}
+
+func convertMap(v reflect.Value, ty reflect.Type) (reflect.Value, error) {
+	if v.IsNil() {
+		return reflect.Zero(ty), nil
+	}
+	res := reflect.MakeMapWithSize(ty, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := convertValue(iter.Key().Interface(), ty.Key())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("key %v: %w", iter.Key().Interface(), err)
+		}
+		elem, err := convertValue(iter.Value().Interface(), ty.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("key %v: %w", iter.Key().Interface(), err)
+		}
+		res.SetMapIndex(key, elem)
+	}
+	return res, nil
+}
+
+// parseString parses a string into a number or bool of type ty.
+func parseString(str string, ty reflect.Type) (reflect.Value, error) {
+	res := reflect.New(ty).Elem()
+	switch {
+	case isIntKind(ty.Kind()):
+		i, err := strconv.ParseInt(str, 10, ty.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		res.SetInt(i)
+	case isUintKind(ty.Kind()):
+		u, err := strconv.ParseUint(str, 10, ty.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		res.SetUint(u)
+	case isFloatKind(ty.Kind()):
+		f, err := strconv.ParseFloat(str, ty.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		res.SetFloat(f)
+	case ty.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		res.SetBool(b)
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot parse string into %s", ty.String())
+	}
+	return res, nil
+}
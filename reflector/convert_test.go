@@ -0,0 +1,106 @@
+package reflector
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertValue(t *testing.T) {
+	t.Parallel()
+	for _, data := range []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{value: 1, expected: 1},
+		{value: 1, expected: int64(1)},
+		{value: float64(17), expected: 17},
+		{value: float64(17), expected: uint8(17)},
+		{value: int8(-3), expected: float32(-3)},
+		{value: uint(3), expected: int16(3)},
+		{value: "aaa", expected: UpperString("aaa")},
+		{value: "aaa", expected: []byte("aaa")},
+		{value: []byte("aaa"), expected: "aaa"},
+		{value: nil, expected: (*int)(nil)},
+		{value: "17", expected: 17},
+		{value: "-17", expected: int8(-17)},
+		{value: "17", expected: uint(17)},
+		{value: "1.5", expected: 1.5},
+		{value: "true", expected: true},
+		{value: []string{"1", "2"}, expected: []int{1, 2}},
+		{value: []interface{}{"1", float64(2)}, expected: []int64{1, 2}},
+		{value: [2]int{1, 2}, expected: []float64{1, 2}},
+		{value: []string(nil), expected: []int(nil)},
+		{value: map[string]interface{}{"1": "2"}, expected: map[int]float64{1: 2}},
+	} {
+		v, err := convertValue(data.value, reflect.TypeOf(data.expected))
+		assert.Nil(t, err, "%#v", data)
+		assert.Equal(t, data.expected, v.Interface(), "%#v", data)
+	}
+}
+
+func TestConvertValueErrors(t *testing.T) {
+	t.Parallel()
+	for _, data := range []struct {
+		value  interface{}
+		target interface{}
+	}{
+		{value: 1.5, target: 1},
+		{value: 300, target: int8(1)},
+		{value: -1, target: uint(1)},
+		{value: float64(math.MaxFloat64), target: float32(1)},
+		{value: uint64(math.MaxUint64), target: int64(1)},
+		{value: 65, target: ""},
+		{value: nil, target: 1},
+		{value: "a", target: 1},
+		{value: "300", target: int8(1)},
+		{value: "-1", target: uint(1)},
+		{value: "yes", target: true},
+		{value: []string{"1", "a"}, target: []int{}},
+		{value: map[string]string{"a": "1"}, target: map[int]int{}},
+		{value: map[string]string{"1": "a"}, target: map[int]int{}},
+	} {
+		_, err := convertValue(data.value, reflect.TypeOf(data.target))
+		assert.NotNil(t, err, "%#v", data)
+	}
+}
+
+func TestConvertSliceErrorIndex(t *testing.T) {
+	t.Parallel()
+	_, err := convertValue([]string{"1", "2", "x"}, reflect.TypeOf([]int{}))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "index 2:")
+}
+
+type ConvertTarget struct {
+	Int64  int64
+	Int    int
+	Bytes  []byte
+	Name   UpperString
+	Floats []float64
+}
+
+func TestSetConvert(t *testing.T) {
+	t.Parallel()
+	var target ConvertTarget
+	obj := New(&target)
+	assert.Nil(t, obj.Field("Int64").SetConvert(17))
+	assert.Nil(t, obj.Field("Int").SetConvert(float64(18)))
+	assert.Nil(t, obj.Field("Bytes").SetConvert("abc"))
+	assert.Nil(t, obj.Field("Name").SetConvert("john"))
+	assert.Nil(t, obj.Field("Floats").SetConvert([]interface{}{1, "2.5"}))
+	assert.Equal(t, ConvertTarget{Int64: 17, Int: 18, Bytes: []byte("abc"), Name: "john", Floats: []float64{1, 2.5}}, target)
+
+	// Strict Set doesn't convert:
+	assert.NotNil(t, obj.Field("Int64").Set(17))
+
+	err := obj.Field("Int").SetConvert(1.5)
+	assert.NotNil(t, err)
+	assert.Equal(t, "field Int in *reflector.ConvertTarget: value 1.5 is not an integer", err.Error())
+	assert.NotNil(t, obj.Field("Int").SetConvert(struct{}{}))
+	assert.NotNil(t, New(target).Field("Int").SetConvert(1))
+	assert.NotNil(t, obj.Field("Invalid").SetConvert(1))
+	assert.Equal(t, 18, target.Int)
+}
@@ -0,0 +1,101 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DeepCopy returns a new Obj wrapping a deep copy of the object's value. Pointers, structs, slices, arrays, maps
+// and interface values are copied recursively, so the copy doesn't share any memory with the original. Pointers
+// shared inside the original value (including cycles) are shared in the same way in the copy.
+//
+// Unexported fields can't be set with reflection, they are shallow-copied (pointers, slices and maps in them are
+// shared with the original). Channels and funcs are shared, too.
+//
+// If the object is not a pointer, the returned object wraps an addressable copy (like NewCopy).
+func (o *Obj) DeepCopy() (*Obj, error) {
+	if !o.IsValid() {
+		return nil, fmt.Errorf("cannot copy nil")
+	}
+	defer o.rlock()()
+
+	c := deepCopier{pointers: map[typedPointer]reflect.Value{}}
+	copied := c.copy(o.currentValue())
+	if o.IsPtr() {
+		return New(copied.Interface()), nil
+	}
+	addressable := reflect.New(copied.Type()).Elem()
+	addressable.Set(copied)
+	return newFromValue(addressable), nil
+}
+
+// typedPointer identifies a pointed-to value (pointers to a struct and to its first field have the same address).
+type typedPointer struct {
+	ptr uintptr
+	ty  reflect.Type
+}
+
+type deepCopier struct {
+	// Copies of already copied pointers:
+	pointers map[typedPointer]reflect.Value
+}
+
+func (c *deepCopier) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		key := typedPointer{ptr: v.Pointer(), ty: v.Type()}
+		if copied, found := c.pointers[key]; found {
+			return copied
+		}
+		copied := reflect.New(v.Type().Elem())
+		c.pointers[key] = copied
+		copied.Elem().Set(c.copy(v.Elem()))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(c.copy(v.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		// Unexported fields are copied by value:
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				copied.Field(i).Set(c.copy(v.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(c.copy(iter.Key()), c.copy(iter.Value()))
+		}
+		return copied
+	}
+	return v
+}
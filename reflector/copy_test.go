@@ -0,0 +1,95 @@
+package reflector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type CopyFixture struct {
+	Name     string
+	Tags     []string
+	Address  *Address
+	Same     *Address
+	Counts   map[string][]int
+	Corners  [2]*Point
+	Any      interface{}
+	Created  time.Time
+	Next     *CopyFixture
+	internal *Address
+}
+
+func TestDeepCopy(t *testing.T) {
+	t.Parallel()
+	address := &Address{Street: "Main"}
+	original := &CopyFixture{
+		Name:     "John",
+		Tags:     []string{"a", "b"},
+		Address:  address,
+		Same:     address,
+		Counts:   map[string][]int{"x": {1, 2}},
+		Corners:  [2]*Point{{X: 1}, nil},
+		Any:      &Point{Y: 2},
+		Created:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		internal: address,
+	}
+	original.Next = original
+
+	obj, err := New(original).DeepCopy()
+	assert.Nil(t, err)
+	copied, err := As[*CopyFixture](obj)
+	assert.Nil(t, err)
+
+	assert.Equal(t, original.Name, copied.Name)
+	assert.Equal(t, original.Tags, copied.Tags)
+	assert.Equal(t, *original.Address, *copied.Address)
+	assert.Equal(t, original.Counts, copied.Counts)
+	assert.Equal(t, original.Any, copied.Any)
+	assert.True(t, original.Created.Equal(copied.Created))
+
+	// No shared memory:
+	assert.NotSame(t, original.Address, copied.Address)
+	copied.Tags[0] = "changed"
+	copied.Counts["x"][0] = 100
+	copied.Corners[0].X = 100
+	copied.Any.(*Point).Y = 100
+	copied.Address.Street = "Other"
+	assert.Equal(t, []string{"a", "b"}, original.Tags)
+	assert.Equal(t, []int{1, 2}, original.Counts["x"])
+	assert.Equal(t, 1, original.Corners[0].X)
+	assert.Equal(t, 2, original.Any.(*Point).Y)
+	assert.Equal(t, "Main", original.Address.Street)
+	assert.Nil(t, copied.Corners[1])
+
+	// Shared pointers stay shared in the copy, cycles are preserved:
+	assert.Same(t, copied.Address, copied.Same)
+	assert.Same(t, copied, copied.Next)
+	// Unexported fields are shallow copies:
+	assert.Same(t, original.internal, copied.internal)
+}
+
+func TestDeepCopyValue(t *testing.T) {
+	t.Parallel()
+	original := Table{Name: "t", Rows: []Address{{Street: "a"}}}
+	obj, err := New(original).DeepCopy()
+	assert.Nil(t, err)
+	assert.False(t, obj.IsPtr())
+
+	// The copy is addressable:
+	assert.Nil(t, obj.Field("Name").Set("changed"))
+	copied, err := As[Table](obj)
+	assert.Nil(t, err)
+	assert.Equal(t, "changed", copied.Name)
+	assert.Equal(t, "t", original.Name)
+
+	copied.Rows[0].Street = "b"
+	assert.Equal(t, "a", original.Rows[0].Street)
+
+	n, err := New(17).DeepCopy()
+	assert.Nil(t, err)
+	assert.Equal(t, 17, n.currentValue().Interface())
+
+	_, err = New(nil).DeepCopy()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,101 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// decodeTag is the tag used to select a registered field decoder, for example `decode:"csv"`.
+const decodeTag = "decode"
+
+// FieldDecoder decodes a raw string into a field.
+type FieldDecoder func(raw string, field *ObjField) error
+
+var (
+	fieldDecoders      = map[string]FieldDecoder{}
+	fieldDecodersMutex sync.RWMutex
+)
+
+// RegisterFieldDecoder registers a decoder for fields tagged with `decode:"<tagValue>"`.
+// Registering a nil decoder removes the existing one.
+func RegisterFieldDecoder(tagValue string, fn func(raw string, field *ObjField) error) {
+	fieldDecodersMutex.Lock()
+	defer fieldDecodersMutex.Unlock()
+
+	if fn == nil {
+		delete(fieldDecoders, tagValue)
+		return
+	}
+	fieldDecoders[tagValue] = fn
+}
+
+// fieldDecoder returns the decoder registered for the field's decode tag (if any).
+func fieldDecoder(of *ObjField) (FieldDecoder, bool) {
+	tagValue := of.structField.Tag.Get(decodeTag)
+	if tagValue == "" {
+		return nil, false
+	}
+
+	fieldDecodersMutex.RLock()
+	defer fieldDecodersMutex.RUnlock()
+
+	fn, found := fieldDecoders[tagValue]
+	return fn, found
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// SetFromString parses the string into the field type and sets the field, useful for values from query params or
+// environment variables. Numbers and bools are parsed with strconv, time.Time as RFC3339 and time.Duration with
+// time.ParseDuration. Pointer fields are set to a newly allocated parsed value. If a decoder is registered for the
+// field's decode tag (see RegisterFieldDecoder), it's used instead.
+func (of *ObjField) SetFromString(s string) error {
+	if err := of.assertValid(); err != nil {
+		return err
+	}
+	if fn, found := fieldDecoder(of); found {
+		return fn(s, of)
+	}
+	if !of.IsSettable() {
+		return fmt.Errorf("field %s in %T not settable", of.name, of.obj.iface)
+	}
+
+	v, err := parseStringValue(s, of.fieldType)
+	if err != nil {
+		return fmt.Errorf("field %s: cannot parse %q as %s: %w", of.name, s, of.fieldType.String(), err)
+	}
+
+	defer of.obj.lock()()
+	of.value.Set(v)
+	return nil
+}
+
+func parseStringValue(s string, ty reflect.Type) (reflect.Value, error) {
+	switch {
+	case ty == timeType:
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(t), nil
+	case ty == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	case ty.Kind() == reflect.String:
+		return reflect.ValueOf(s).Convert(ty), nil
+	case ty.Kind() == reflect.Ptr:
+		elem, err := parseStringValue(s, ty.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		res := reflect.New(ty.Elem())
+		res.Elem().Set(elem)
+		return res, nil
+	}
+	return parseString(s, ty)
+}
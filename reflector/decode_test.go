@@ -0,0 +1,104 @@
+package reflector
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type WithDecoders struct {
+	Tags    []string `decode:"csv"`
+	Unknown string   `decode:"nothing_registered"`
+	Plain   string
+}
+
+func init() {
+	RegisterFieldDecoder("csv", func(raw string, field *ObjField) error {
+		return field.Set(strings.Split(raw, ","))
+	})
+}
+
+func TestFieldDecoder(t *testing.T) {
+	t.Parallel()
+	var s WithDecoders
+	obj := New(&s)
+
+	assert.Nil(t, obj.Field("Tags").SetFromString("a,b,c"))
+	assert.Equal(t, []string{"a", "b", "c"}, s.Tags)
+
+	assert.Nil(t, obj.FromNestedMap(map[string]interface{}{"Tags": "x,y", "Unknown": "aaa", "Plain": "bbb"}))
+	assert.Equal(t, []string{"x", "y"}, s.Tags)
+	// No decoder registered for the tag, set as a plain value:
+	assert.Equal(t, "aaa", s.Unknown)
+	assert.Equal(t, "bbb", s.Plain)
+
+	assert.NotNil(t, obj.Field("Invalid").SetFromString("aaa"))
+}
+
+func TestFieldDecoderUnregister(t *testing.T) {
+	RegisterFieldDecoder("tmp_decoder", func(raw string, field *ObjField) error { return nil })
+	_, found := fieldDecoders["tmp_decoder"]
+	assert.True(t, found)
+
+	RegisterFieldDecoder("tmp_decoder", nil)
+	_, found = fieldDecoders["tmp_decoder"]
+	assert.False(t, found)
+}
+
+type EnvConfig struct {
+	Host     string
+	Port     uint16
+	Ratio    float64
+	Debug    bool
+	Level    UpperString
+	Started  time.Time
+	Timeout  time.Duration
+	Retries  *int
+	Tags     []string `decode:"csv"`
+	Children []string
+}
+
+func TestSetFromString(t *testing.T) {
+	t.Parallel()
+	var c EnvConfig
+	obj := New(&c)
+	for name, value := range map[string]string{
+		"Host":    "localhost",
+		"Port":    "8080",
+		"Ratio":   "0.5",
+		"Debug":   "true",
+		"Level":   "info",
+		"Started": "2020-01-02T03:04:05Z",
+		"Timeout": "1m30s",
+		"Retries": "3",
+		"Tags":    "a,b",
+	} {
+		assert.Nil(t, obj.Field(name).SetFromString(value), name)
+	}
+	retries := 3
+	assert.Equal(t, EnvConfig{
+		Host:    "localhost",
+		Port:    8080,
+		Ratio:   0.5,
+		Debug:   true,
+		Level:   "info",
+		Started: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Timeout: 90 * time.Second,
+		Retries: &retries,
+		Tags:    []string{"a", "b"},
+	}, c)
+
+	err := obj.Field("Port").SetFromString("70000")
+	assert.NotNil(t, err)
+	assert.Equal(t, `field Port: cannot parse "70000" as uint16: strconv.ParseUint: parsing "70000": value out of range`, err.Error())
+	err = obj.Field("Started").SetFromString("yesterday")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `field Started: cannot parse "yesterday" as time.Time`)
+	assert.NotNil(t, obj.Field("Debug").SetFromString("maybe"))
+	assert.NotNil(t, obj.Field("Children").SetFromString("a"))
+	assert.NotNil(t, obj.Field("Invalid").SetFromString("a"))
+	assert.NotNil(t, New(c).Field("Host").SetFromString("a"))
+	assert.Equal(t, uint16(8080), c.Port)
+}
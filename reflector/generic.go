@@ -0,0 +1,43 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewTyped initializes a new Obj wrapper, like New but with the value's type known at compile time.
+func NewTyped[T any](v T) *Obj {
+	return New(v)
+}
+
+// NewZero initializes a new Obj wrapper around a zero value of T.
+//
+// If T is a pointer (for example *Person), the object wraps a pointer to a newly allocated zero value. Otherwise
+// the object wraps an addressable zero value (like NewCopy), in both cases fields are settable.
+func NewZero[T any]() *Obj {
+	ty := reflect.TypeOf((*T)(nil)).Elem()
+	if ty.Kind() == reflect.Ptr {
+		return New(reflect.New(ty.Elem()).Interface())
+	}
+	return newFromValue(reflect.New(ty).Elem())
+}
+
+// As returns the object's (current) value as T, or an error if the value isn't a T.
+// A nil object can be returned as any T which can be nil (pointers, interfaces, maps, ...).
+func As[T any](o *Obj) (T, error) {
+	var zero T
+	ty := reflect.TypeOf((*T)(nil)).Elem()
+	if !o.IsValid() {
+		switch ty.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+			return zero, nil
+		}
+		return zero, fmt.Errorf("cannot use nil as %s", ty.String())
+	}
+
+	res, is := o.currentValue().Interface().(T)
+	if !is {
+		return zero, fmt.Errorf("cannot use %s as %s", o.String(), ty.String())
+	}
+	return res, nil
+}
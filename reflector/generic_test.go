@@ -0,0 +1,70 @@
+package reflector
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTyped(t *testing.T) {
+	t.Parallel()
+	p := &Person{Name: "John"}
+	obj := NewTyped(p)
+	assert.True(t, obj.IsPtr())
+	assert.Nil(t, obj.Field("Name").Set("Jane"))
+	assert.Equal(t, "Jane", p.Name)
+
+	res, err := As[*Person](obj)
+	assert.Nil(t, err)
+	assert.Same(t, p, res)
+
+	_, err = As[Person](obj)
+	assert.NotNil(t, err)
+	assert.Equal(t, "cannot use *reflector.Person as reflector.Person", err.Error())
+
+	// Interfaces:
+	e, err := As[error](NewTyped(&MyError{Code: 1}))
+	assert.Nil(t, err)
+	assert.Equal(t, "my error 1", e.Error())
+	_, err = As[fmt.Stringer](NewTyped(&MyError{Code: 1}))
+	assert.NotNil(t, err)
+	var i interface{} = 17
+	n, err := As[int](NewTyped(i))
+	assert.Nil(t, err)
+	assert.Equal(t, 17, n)
+}
+
+func TestNewZero(t *testing.T) {
+	t.Parallel()
+	{
+		obj := NewZero[Person]()
+		assert.False(t, obj.IsPtr())
+		assert.Nil(t, obj.Field("Name").Set("John"))
+		p, err := As[Person](obj)
+		assert.Nil(t, err)
+		assert.Equal(t, Person{Name: "John"}, p)
+	}
+	{
+		obj := NewZero[*Person]()
+		assert.True(t, obj.IsPtr())
+		assert.Nil(t, obj.Field("Street").Set("Main"))
+		p, err := As[*Person](obj)
+		assert.Nil(t, err)
+		assert.Equal(t, &Person{Address: Address{Street: "Main"}}, p)
+	}
+	{
+		n, err := As[int](NewZero[int]())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, n)
+	}
+	{
+		obj := NewZero[fmt.Stringer]()
+		assert.False(t, obj.IsValid())
+		s, err := As[fmt.Stringer](obj)
+		assert.Nil(t, err)
+		assert.Nil(t, s)
+		_, err = As[int](obj)
+		assert.NotNil(t, err)
+	}
+}
@@ -0,0 +1,54 @@
+package reflector
+
+import "sync"
+
+// CallHook is invoked after a method call made with ObjMethod.Call.
+type CallHook func(methodName string, args []interface{}, result *CallResult, err error)
+
+type namedCallHook struct {
+	name string
+	fn   CallHook
+}
+
+var (
+	callHooks      []namedCallHook
+	callHooksMutex sync.RWMutex
+)
+
+// RegisterCallHook registers a hook (by name) invoked after every ObjMethod.Call (also when the call failed, in
+// that case result is nil and err is not). Registering a hook with an existing name replaces that hook, and
+// registering a nil hook removes it (like with RegisterFieldDecoder).
+//
+// Hooks are invoked synchronously, in registration order, in the goroutine making the call. Since calls can be
+// made from multiple goroutines at the same time, hooks must be safe for concurrent use. Hooks can call methods
+// (and register or remove hooks), changes apply to subsequent calls.
+func RegisterCallHook(name string, fn func(methodName string, args []interface{}, result *CallResult, err error)) {
+	callHooksMutex.Lock()
+	defer callHooksMutex.Unlock()
+
+	// Copy on write, so that hooks being run (see runCallHooks) are not changed:
+	hooks := make([]namedCallHook, 0, len(callHooks)+1)
+	replaced := false
+	for _, hook := range callHooks {
+		if hook.name != name {
+			hooks = append(hooks, hook)
+		} else if fn != nil {
+			hooks = append(hooks, namedCallHook{name: name, fn: fn})
+			replaced = true
+		}
+	}
+	if fn != nil && !replaced {
+		hooks = append(hooks, namedCallHook{name: name, fn: fn})
+	}
+	callHooks = hooks
+}
+
+func runCallHooks(methodName string, args []interface{}, result *CallResult, err error) {
+	callHooksMutex.RLock()
+	hooks := callHooks
+	callHooksMutex.RUnlock()
+
+	for _, hook := range hooks {
+		hook.fn(methodName, args, result, err)
+	}
+}
@@ -0,0 +1,69 @@
+package reflector
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallHooks(t *testing.T) {
+	var calls []string
+	RegisterCallHook("log", func(methodName string, args []interface{}, result *CallResult, err error) {
+		if err != nil {
+			calls = append(calls, fmt.Sprintf("%s%v: %s", methodName, args, err.Error()))
+			return
+		}
+		calls = append(calls, fmt.Sprintf("%s%v=%v", methodName, args, result.Result))
+	})
+	RegisterCallHook("second", func(methodName string, args []interface{}, result *CallResult, err error) {
+		calls = append(calls, "second")
+	})
+	defer RegisterCallHook("log", nil)
+	defer RegisterCallHook("second", nil)
+
+	obj := New(&Person{})
+	_, _ = obj.Method("Add").Call(1, 2, 3)
+	_, _ = obj.Method("Invalid").Call()
+	assert.Equal(t, []string{
+		"Add[1 2 3]=[6]",
+		"second",
+		"Invalid[]: invalid method Invalid in *reflector.Person",
+		"second",
+	}, calls)
+
+	// Replaced (in the same position):
+	RegisterCallHook("log", func(methodName string, args []interface{}, result *CallResult, err error) {
+		calls = append(calls, "replaced")
+	})
+	_, _ = obj.Method("Add").Call(1, 2, 3)
+	assert.Equal(t, []string{"replaced", "second"}, calls[4:])
+
+	// Only the named hook is removed:
+	RegisterCallHook("log", nil)
+	_, _ = obj.Method("Add").Call(1, 2, 3)
+	assert.Equal(t, []string{"second"}, calls[6:])
+
+	RegisterCallHook("second", nil)
+	_, _ = obj.Method("Add").Call(1, 2, 3)
+	assert.Equal(t, 7, len(calls))
+}
+
+func TestCallHookCallingMethods(t *testing.T) {
+	var nested []interface{}
+	RegisterCallHook("nested", func(methodName string, args []interface{}, result *CallResult, err error) {
+		if methodName != "Add" {
+			return
+		}
+		// Calls (and hook registrations) from hooks don't deadlock:
+		res, err := New(Person{}).Method("Hi").Call("John")
+		assert.Nil(t, err)
+		nested = append(nested, res.Result...)
+		RegisterCallHook("other", nil)
+	})
+	defer RegisterCallHook("nested", nil)
+
+	_, err := New(Person{}).Method("Add").Call(1, 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Hi John my name is "}, nested)
+}
@@ -0,0 +1,430 @@
+package reflector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// ToNestedMap converts a struct (or pointer to struct) into a map of exported field values.
+//
+// Nested structs are converted into nested maps, while fields of embedded structs are promoted
+// into the parent map (the same way encoding/json does it). Values implementing json.Marshaler
+// are marshaled and the decoded JSON is used instead of their struct decomposition.
+//
+// Cyclic values (a pointer pointing back to a value being converted) result in an error.
+func (o *Obj) ToNestedMap() (map[string]interface{}, error) {
+	if !o.IsStructOrPtrToStruct() {
+		return nil, fmt.Errorf("cannot convert %s to map", o.String())
+	}
+	if !o.fieldsValue.IsValid() {
+		return nil, fmt.Errorf("cannot convert nil %s to map", o.String())
+	}
+	visiting := map[typedPointer]bool{}
+	if o.fieldsValue.CanAddr() {
+		visiting[typedPointer{ptr: o.fieldsValue.Addr().Pointer(), ty: reflect.PtrTo(o.fieldsValue.Type())}] = true
+	}
+	return structToNestedMap(o.fieldsValue, visiting)
+}
+
+// ToMap converts a struct (or pointer to struct) into a flat map of exported field values, with fields of
+// embedded structs listed as if declared in the outer struct (see FieldsFlattened). Keys are field names, or
+// tag values with WithTagKey. Pointer fields are dereferenced (nil pointers are nil values).
+func (o *Obj) ToMap(opts ...MapOption) (map[string]interface{}, error) {
+	if !o.IsStructOrPtrToStruct() {
+		return nil, fmt.Errorf("cannot convert %s to map", o.String())
+	}
+	if !o.fieldsValue.IsValid() {
+		return nil, fmt.Errorf("cannot convert nil %s to map", o.String())
+	}
+	options := newMapOptions(opts)
+
+	defer o.rlock()()
+	res := map[string]interface{}{}
+	visited := map[string]bool{}
+	for _, name := range o.fieldNamesFlattenAnonymous {
+		field := o.Field(name)
+		if visited[name] || !field.IsValid() || !field.IsExported() {
+			continue
+		}
+		visited[name] = true
+
+		key := name
+		if options.tagName != "" {
+			var skip bool
+			if key, skip = tagKey(field.structField, options.tagName); skip {
+				continue
+			}
+		}
+		value := field.value
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				res[key] = nil
+				continue
+			}
+			value = value.Elem()
+		}
+		res[key] = value.Interface()
+	}
+	return res, nil
+}
+
+// structToNestedMap converts the struct into a map, visiting contains pointers currently being converted.
+func structToNestedMap(v reflect.Value, visiting map[typedPointer]bool) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	ty := v.Type()
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		fieldValue := v.Field(i)
+		if field.Anonymous && !isJSONMarshaler(fieldValue) {
+			embedded := reflect.Indirect(fieldValue)
+			if embedded.Kind() == reflect.Struct {
+				var embeddedMap map[string]interface{}
+				var err error
+				if fieldValue.Kind() == reflect.Ptr {
+					embeddedMap, err = pointedToNestedMap(fieldValue, visiting)
+				} else {
+					embeddedMap, err = structToNestedMap(embedded, visiting)
+				}
+				if err != nil {
+					return nil, err
+				}
+				for key, value := range embeddedMap {
+					// Fields declared in the outer struct have precedence:
+					if _, found := ty.FieldByName(key); found && !isPromotedFrom(ty, key, i) {
+						continue
+					}
+					if _, found := res[key]; !found {
+						res[key] = value
+					}
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		value, err := nestedMapValue(fieldValue, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		res[field.Name] = value
+	}
+	return res, nil
+}
+
+// isPromotedFrom checks if the field name (as seen from ty) is the one declared inside the embedded field with index embeddedIndex.
+func isPromotedFrom(ty reflect.Type, name string, embeddedIndex int) bool {
+	field, found := ty.FieldByName(name)
+	return found && len(field.Index) > 1 && field.Index[0] == embeddedIndex
+}
+
+func isJSONMarshaler(v reflect.Value) bool {
+	if v.Type().Implements(jsonMarshalerType) {
+		return true
+	}
+	return v.CanAddr() && reflect.PtrTo(v.Type()).Implements(jsonMarshalerType)
+}
+
+func nestedMapValue(v reflect.Value, visiting map[typedPointer]bool) (interface{}, error) {
+	if isJSONMarshaler(v) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, nil
+		}
+		if !v.Type().Implements(jsonMarshalerType) {
+			v = v.Addr()
+		}
+		byts, err := v.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var res interface{}
+		if err := json.Unmarshal(byts, &res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Elem().Kind() == reflect.Struct {
+			return pointedToNestedMap(v, visiting)
+		}
+	case reflect.Struct:
+		return structToNestedMap(v, visiting)
+	}
+	return v.Interface(), nil
+}
+
+// pointedToNestedMap converts the struct the (non nil) pointer points to, unless it's already being converted.
+func pointedToNestedMap(v reflect.Value, visiting map[typedPointer]bool) (map[string]interface{}, error) {
+	key := typedPointer{ptr: v.Pointer(), ty: v.Type()}
+	if visiting[key] {
+		return nil, fmt.Errorf("encountered a cycle via %s", v.Type().String())
+	}
+	visiting[key] = true
+	defer delete(visiting, key)
+	return structToNestedMap(v.Elem(), visiting)
+}
+
+// FromNestedMap sets struct fields from a (flat or nested) map, the inverse of ToMap and ToNestedMap. The object
+// must be a pointer to struct, otherwise an error is returned and nothing is set.
+//
+// Keys are field names (or tag values with WithTagKey). A nested map[string]interface{} value for a struct (or
+// pointer to struct) field is bound recursively into that field, nil pointers are allocated when needed. Other
+// values are converted to the field type when possible (for example a float64 into an int field), values for
+// pointer fields can be either pointers or values of the element type, and string values for fields with a
+// registered decoder (see RegisterFieldDecoder) are decoded.
+//
+// Fields are set in declaration order, and an embedded struct is always set (outer before inner) before the fields
+// promoted from it, so a promoted key overrides the same field in the embedded struct's nested map. Keys not
+// matching any field are ignored. Errors are collected and returned together, fields without errors are set
+// anyway.
+//
+// Read-only fields (tagged with `access:"readonly"`, see WithReadonlyTag and WithStrictReadonly) are never set,
+// and neither are fields promoted from read-only embedded structs.
+func (o *Obj) FromNestedMap(data map[string]interface{}, opts ...MapOption) error {
+	if err := o.assertBindable(); err != nil {
+		return err
+	}
+	b := newMapBinder(opts)
+	b.bind(o, data, "")
+	return b.errs.asError()
+}
+
+// UnmarshalJSONInto decodes a JSON object and binds it like FromNestedMap, but with keys taken from `json` tags.
+//
+// This is more lenient than encoding/json, because values are converted (for example "17" into an int field).
+// Numbers are decoded as json.Number (to avoid losing precision), so interface{} fields will contain json.Number
+// values.
+func (o *Obj) UnmarshalJSONInto(data []byte, opts ...MapOption) error {
+	if err := o.assertBindable(); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var m map[string]interface{}
+	if err := decoder.Decode(&m); err != nil {
+		return err
+	}
+	b := newMapBinder(append([]MapOption{WithTagKey("json")}, opts...))
+	b.bind(o, m, "")
+	return b.errs.asError()
+}
+
+// FromNestedMapTracked binds the map like FromNestedMap, and returns paths of fields which were successfully
+// set (for example "Name" or "Address.Street" for values from nested maps). Fields which failed are not listed.
+func (o *Obj) FromNestedMapTracked(data map[string]interface{}, opts ...MapOption) ([]string, error) {
+	if err := o.assertBindable(); err != nil {
+		return nil, err
+	}
+	b := newMapBinder(opts)
+	b.bind(o, data, "")
+	return b.set, b.errs.asError()
+}
+
+// FromMapRequiring binds the map like FromNestedMap, but only if all required keys are present in data.
+// If any of them is missing, an error listing the missing keys is returned and nothing is set.
+func (o *Obj) FromMapRequiring(data map[string]interface{}, required []string, opts ...MapOption) error {
+	if err := o.assertBindable(); err != nil {
+		return err
+	}
+	var missing []string
+	for _, name := range required {
+		if _, found := data[name]; !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+	return o.FromNestedMap(data, opts...)
+}
+
+func (o *Obj) assertBindable() error {
+	if !o.isPtrToStruct {
+		return fmt.Errorf("cannot bind into %s, pointer to struct required", o.String())
+	}
+	if !o.fieldsValue.IsValid() {
+		return fmt.Errorf("cannot bind into nil %s", o.String())
+	}
+	return nil
+}
+
+// MapOption configures conversions between structs and maps.
+type MapOption func(*mapOptions)
+
+type mapOptions struct {
+	// If not empty, map keys are taken from this tag (with the field name as fallback):
+	tagName string
+
+	readonlyTagName  string
+	readonlyTagValue string
+	strictReadonly   bool
+}
+
+func newMapOptions(opts []MapOption) mapOptions {
+	res := mapOptions{readonlyTagName: "access", readonlyTagValue: "readonly"}
+	for _, opt := range opts {
+		opt(&res)
+	}
+	return res
+}
+
+// WithTagKey uses the tag (for example "json") as the map key, fields without the tag use the field name and
+// fields tagged with "-" are skipped.
+func WithTagKey(tagName string) MapOption {
+	return func(mo *mapOptions) {
+		mo.tagName = tagName
+	}
+}
+
+// WithReadonlyTag changes the tag marking read-only fields, the default is `access:"readonly"`. The tag value
+// can be a comma separated list (for example `access:"readonly,internal"`).
+func WithReadonlyTag(tagName, tagValue string) MapOption {
+	return func(mo *mapOptions) {
+		mo.readonlyTagName, mo.readonlyTagValue = tagName, tagValue
+	}
+}
+
+// WithStrictReadonly makes binding report an error for input values of read-only fields, instead of silently
+// ignoring them.
+func WithStrictReadonly() MapOption {
+	return func(mo *mapOptions) {
+		mo.strictReadonly = true
+	}
+}
+
+func (mo mapOptions) isReadonly(field reflect.StructField) bool {
+	if mo.readonlyTagName == "" {
+		return false
+	}
+	for _, value := range strings.Split(field.Tag.Get(mo.readonlyTagName), ",") {
+		if strings.TrimSpace(value) == mo.readonlyTagValue {
+			return true
+		}
+	}
+	return false
+}
+
+// isReadonlyField returns true if the field (with the index in ty) or any embedded struct it's promoted through is
+// read-only.
+func (mo mapOptions) isReadonlyField(ty reflect.Type, index []int) bool {
+	for _, i := range index {
+		for ty.Kind() == reflect.Ptr {
+			ty = ty.Elem()
+		}
+		field := ty.Field(i)
+		if mo.isReadonly(field) {
+			return true
+		}
+		ty = field.Type
+	}
+	return false
+}
+
+type mapBinder struct {
+	mapOptions
+
+	errs errorList
+	// Paths of successfully set fields:
+	set []string
+}
+
+func newMapBinder(opts []MapOption) *mapBinder {
+	return &mapBinder{mapOptions: newMapOptions(opts)}
+}
+
+func (b *mapBinder) bind(o *Obj, data map[string]interface{}, prefix string) {
+	visited := map[string]bool{}
+	for _, name := range o.fieldNamesAll {
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+
+		field := o.Field(name)
+		key := name
+		if b.tagName != "" {
+			var skip bool
+			if key, skip = tagKey(field.structField, b.tagName); skip {
+				continue
+			}
+		}
+		value, found := data[key]
+		if !found {
+			continue
+		}
+		if b.isReadonlyField(o.underlyingType, field.structField.Index) {
+			if b.strictReadonly {
+				b.errs = append(b.errs, fmt.Errorf("field %s: read-only", prefix+name))
+			}
+			continue
+		}
+		nested, err := b.bindField(field, value, prefix+name)
+		if err != nil {
+			b.errs = append(b.errs, fmt.Errorf("field %s: %w", prefix+name, err))
+		} else if !nested {
+			b.set = append(b.set, prefix+name)
+		}
+	}
+}
+
+// bindField sets the field value, nested is true if the value was a map bound recursively into the field.
+func (b *mapBinder) bindField(field *ObjField, value interface{}, path string) (nested bool, err error) {
+	if field.valid && !field.value.IsValid() && field.obj.fieldsValue.IsValid() {
+		// Promoted field declared in a nil embedded pointer:
+		if field.value, err = fieldByIndexAlloc(field.obj.fieldsValue, field.structField.Index); err != nil {
+			return false, err
+		}
+	}
+	if err := field.assertValid(); err != nil {
+		return false, err
+	}
+	if !field.IsSettable() {
+		return false, fmt.Errorf("not settable")
+	}
+
+	if nested, is := value.(map[string]interface{}); is {
+		fieldValue := field.value
+		if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+			if fieldValue.IsNil() {
+				fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+			}
+			b.bind(New(fieldValue.Interface()), nested, path+".")
+			return true, nil
+		}
+		if fieldValue.Kind() == reflect.Struct {
+			b.bind(New(fieldValue.Addr().Interface()), nested, path+".")
+			return true, nil
+		}
+	}
+
+	if raw, is := value.(string); is {
+		if decoder, found := fieldDecoder(field); found {
+			return false, decoder(raw, field)
+		}
+	}
+
+	converted, err := convertValue(value, field.fieldType)
+	if err != nil && value != nil && field.fieldKind == reflect.Ptr {
+		// Try a value of the element type:
+		elem, elemErr := convertValue(value, field.fieldType.Elem())
+		if elemErr == nil {
+			converted, err = reflect.New(field.fieldType.Elem()), nil
+			converted.Elem().Set(elem)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	field.value.Set(converted)
+	return false, nil
+}
@@ -0,0 +1,509 @@
+package reflector
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrajina/go-reflector/reflector/tmp"
+)
+
+type UpperString string
+
+func (us *UpperString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(string(*us)))
+}
+
+type Point struct {
+	X, Y int
+}
+
+type Shape struct {
+	Name    string
+	Center  Point
+	Corner  *Point
+	Missing *Point
+	Created time.Time
+	Label   UpperString
+	Person
+	internal int
+}
+
+func TestToNestedMap(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	shape := Shape{
+		Name:    "square",
+		Center:  Point{X: 1, Y: 2},
+		Corner:  &Point{X: 3, Y: 4},
+		Created: created,
+		Label:   "label",
+		Person:  Person{Name: "Person", Address: Address{Street: "Street", Number: 7}},
+	}
+
+	m, err := New(&shape).ToNestedMap()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		// Declared in Shape, so Person.Name is shadowed:
+		"Name":    "square",
+		"Center":  map[string]interface{}{"X": 1, "Y": 2},
+		"Corner":  map[string]interface{}{"X": 3, "Y": 4},
+		"Missing": nil,
+		// time.Time implements json.Marshaler:
+		"Created": "2020-01-02T03:04:05Z",
+		// Marshaler with a pointer receiver, works because the value is addressable:
+		"Label": "LABEL",
+		// Promoted from Person and Address:
+		"Street": "Street",
+		"Number": 7,
+	}, m)
+
+	// Not addressable, so the pointer receiver MarshalJSON can't be used:
+	m, err = New(shape).ToNestedMap()
+	assert.Nil(t, err)
+	assert.Equal(t, UpperString("label"), m["Label"])
+	assert.Equal(t, "2020-01-02T03:04:05Z", m["Created"])
+}
+
+func TestToNestedMapInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := New(1).ToNestedMap()
+	assert.NotNil(t, err)
+	_, err = New((*Shape)(nil)).ToNestedMap()
+	assert.NotNil(t, err)
+	_, err = New(nil).ToNestedMap()
+	assert.NotNil(t, err)
+}
+
+func TestToNestedMapCyclic(t *testing.T) {
+	t.Parallel()
+	{
+		n := &CycleNode{Name: "a", Next: &CycleNode{Name: "b"}}
+		n.Next.Next = n
+		_, err := New(n).ToNestedMap()
+		assert.NotNil(t, err)
+		assert.Equal(t, "field Next: field Next: encountered a cycle via *reflector.CycleNode", err.Error())
+	}
+	{
+		n := &RecursiveNode{Value: 1}
+		n.RecursiveNode = n
+		_, err := New(n).ToNestedMap()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "encountered a cycle")
+	}
+	{
+		// Shared pointers (without cycles) are fine:
+		address := &Address{Street: "Main"}
+		m, err := New(Building{Address: address, Owners: nil}).ToNestedMap()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"Street": "Main", "Number": 0}, m["Address"])
+		shared := struct{ A, B *Address }{A: address, B: address}
+		m, err = New(shared).ToNestedMap()
+		assert.Nil(t, err)
+		assert.Equal(t, m["A"], m["B"])
+	}
+}
+
+type Employee struct {
+	Name     string
+	Age      int
+	Tags     []string `decode:"csv"`
+	Address  Address
+	Previous *Address
+	Person
+}
+
+func TestFromNestedMap(t *testing.T) {
+	t.Parallel()
+	var e Employee
+	err := New(&e).FromNestedMap(map[string]interface{}{
+		"Name": "John",
+		// JSON numbers are float64:
+		"Age":  float64(30),
+		"Tags": "a,b",
+		"Address": map[string]interface{}{
+			"Street": "Main",
+			"Number": float64(7),
+		},
+		"Previous": map[string]interface{}{
+			"Street": "Old",
+		},
+		"Person": map[string]interface{}{
+			"Name": "Inner",
+		},
+		"Street":  "Promoted",
+		"Unknown": "ignored",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "John", e.Name)
+	assert.Equal(t, 30, e.Age)
+	assert.Equal(t, []string{"a", "b"}, e.Tags)
+	assert.Equal(t, Address{Street: "Main", Number: 7}, e.Address)
+	assert.NotNil(t, e.Previous)
+	assert.Equal(t, Address{Street: "Old"}, *e.Previous)
+	assert.Equal(t, "Inner", e.Person.Name)
+	assert.Equal(t, "Promoted", e.Person.Street)
+}
+
+func TestFromNestedMapEmbeddedPtr(t *testing.T) {
+	t.Parallel()
+	{
+		var w WithAddressPtr
+		assert.Nil(t, New(&w).FromNestedMap(map[string]interface{}{"Name": "John", "Street": "Main", "Number": 7}))
+		assert.Equal(t, "John", w.Name)
+		assert.Equal(t, &Address{Street: "Main", Number: 7}, w.Address)
+	}
+	{
+		// Not allocated without promoted keys:
+		var w WithAddressPtr
+		assert.Nil(t, New(&w).FromNestedMap(map[string]interface{}{"Name": "John"}))
+		assert.Nil(t, w.Address)
+	}
+}
+
+type InnerEmbed struct {
+	Value string
+}
+
+type MiddleEmbed struct {
+	InnerEmbed
+	Label string
+}
+
+type OuterEmbed struct {
+	Title string
+	MiddleEmbed
+}
+
+func TestFromNestedMapEmbedOrder(t *testing.T) {
+	t.Parallel()
+	var o OuterEmbed
+	set, err := New(&o).FromNestedMapTracked(map[string]interface{}{
+		"Value":       "promoted",
+		"InnerEmbed":  map[string]interface{}{"Value": "inner"},
+		"MiddleEmbed": map[string]interface{}{"Label": "label", "InnerEmbed": map[string]interface{}{"Value": "middle"}},
+		"Title":       "title",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Title", "MiddleEmbed.InnerEmbed.Value", "MiddleEmbed.Label", "InnerEmbed.Value", "Value"}, set)
+	assert.Equal(t, OuterEmbed{Title: "title", MiddleEmbed: MiddleEmbed{InnerEmbed: InnerEmbed{Value: "promoted"}, Label: "label"}}, o)
+}
+
+func TestFromNestedMapErrors(t *testing.T) {
+	t.Parallel()
+	var e Employee
+	err := New(&e).FromNestedMap(map[string]interface{}{
+		"Name": 1,
+		"Age":  1.5,
+		"Address": map[string]interface{}{
+			"Number": "aaa",
+		},
+		"Street": "ok",
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "field Name:")
+	assert.Contains(t, err.Error(), "field Age:")
+	assert.Contains(t, err.Error(), "field Address.Number:")
+	// Valid fields are still set:
+	assert.Equal(t, "ok", e.Street)
+
+	assert.NotNil(t, New(e).FromNestedMap(map[string]interface{}{"Name": "John"}))
+	assert.NotNil(t, New((*Employee)(nil)).FromNestedMap(map[string]interface{}{"Name": "John"}))
+	assert.NotNil(t, New(1).FromNestedMap(map[string]interface{}{}))
+}
+
+func TestFromMapRequiring(t *testing.T) {
+	t.Parallel()
+	{
+		var e Employee
+		err := New(&e).FromMapRequiring(map[string]interface{}{"Name": "John", "Age": 30}, []string{"Name", "Age"})
+		assert.Nil(t, err)
+		assert.Equal(t, "John", e.Name)
+		assert.Equal(t, 30, e.Age)
+	}
+	{
+		var e Employee
+		err := New(&e).FromMapRequiring(map[string]interface{}{"Name": "John"}, []string{"Name", "Age", "Tags"})
+		assert.NotNil(t, err)
+		assert.Equal(t, "missing required fields: Age, Tags", err.Error())
+		// Nothing is set when required fields are missing:
+		assert.Equal(t, "", e.Name)
+	}
+	{
+		var e Employee
+		err := New(e).FromMapRequiring(map[string]interface{}{"Name": "John"}, nil)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestFromNestedMapTracked(t *testing.T) {
+	t.Parallel()
+	var e Employee
+	set, err := New(&e).FromNestedMapTracked(map[string]interface{}{
+		"Name": "John",
+		"Age":  "invalid",
+		"Address": map[string]interface{}{
+			"Street": "Main",
+		},
+		"Unknown": 1,
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"Name", "Address.Street"}, set)
+
+	_, err = New(e).FromNestedMapTracked(nil)
+	assert.NotNil(t, err)
+}
+
+func TestFromNestedMapSlices(t *testing.T) {
+	t.Parallel()
+	var table Table
+	err := New(&table).FromNestedMap(map[string]interface{}{
+		// For example, repeated query parameters:
+		"Numbers": []string{"1", "2", "3"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, table.Numbers)
+
+	err = New(&table).FromNestedMap(map[string]interface{}{
+		"Numbers": []interface{}{float64(4), "5", "six"},
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "field Numbers: index 2:")
+	assert.Equal(t, []int{1, 2, 3}, table.Numbers)
+}
+
+type JSONUser struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Age     int    `json:"age"`
+	Secret  string `json:"-"`
+	Untaged string
+	Address *Address          `json:"address"`
+	Extra   interface{}       `json:"extra"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func TestUnmarshalJSONInto(t *testing.T) {
+	t.Parallel()
+	var u JSONUser
+	err := New(&u).UnmarshalJSONInto([]byte(`{
+		"id": 9007199254740993,
+		"name": "John",
+		"age": "30",
+		"Secret": "secret",
+		"Untaged": "untagged",
+		"address": {"Street": "Main", "Number": "7"},
+		"extra": 1.5,
+		"labels": {"a": "b"}
+	}`))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), u.ID)
+	assert.Equal(t, "John", u.Name)
+	assert.Equal(t, 30, u.Age)
+	assert.Equal(t, "", u.Secret)
+	assert.Equal(t, "untagged", u.Untaged)
+	assert.Equal(t, &Address{Street: "Main", Number: 7}, u.Address)
+	assert.Equal(t, json.Number("1.5"), u.Extra)
+	assert.Equal(t, map[string]string{"a": "b"}, u.Labels)
+}
+
+func TestUnmarshalJSONIntoErrors(t *testing.T) {
+	t.Parallel()
+	var u JSONUser
+	assert.NotNil(t, New(&u).UnmarshalJSONInto([]byte(`{"id": `)))
+	assert.NotNil(t, New(&u).UnmarshalJSONInto([]byte(`[]`)))
+	assert.NotNil(t, New(&u).UnmarshalJSONInto([]byte(`{"age": "thirty"}`)))
+	assert.NotNil(t, New(u).UnmarshalJSONInto([]byte(`{}`)))
+}
+
+type Account struct {
+	ID      int     `json:"id" access:"readonly"`
+	Role    string  `json:"role" perm:"internal,readonly"`
+	Name    string  `json:"name"`
+	Address Address `access:"readonly"`
+}
+
+type Audit struct {
+	CreatedBy string `json:"created_by"`
+}
+
+type AuditedDoc struct {
+	Title string `json:"title"`
+	Audit `access:"readonly"`
+}
+
+func TestBindReadonly(t *testing.T) {
+	t.Parallel()
+	data := map[string]interface{}{
+		"ID":      17,
+		"Role":    "admin",
+		"Name":    "John",
+		"Address": map[string]interface{}{"Street": "Main"},
+	}
+	{
+		var a Account
+		assert.Nil(t, New(&a).FromNestedMap(data))
+		assert.Equal(t, Account{Role: "admin", Name: "John"}, a)
+	}
+	{
+		var a Account
+		set, err := New(&a).FromNestedMapTracked(data, WithReadonlyTag("perm", "readonly"))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ID", "Name", "Address.Street"}, set)
+		assert.Equal(t, "", a.Role)
+	}
+	{
+		var a Account
+		err := New(&a).FromNestedMap(data, WithStrictReadonly())
+		assert.NotNil(t, err)
+		assert.Equal(t, "field ID: read-only; field Address: read-only", err.Error())
+		assert.Equal(t, Account{Role: "admin", Name: "John"}, a)
+	}
+	{
+		var a Account
+		assert.Nil(t, New(&a).UnmarshalJSONInto([]byte(`{"id": 1, "role": "admin", "name": "Jane"}`)))
+		assert.Equal(t, Account{Role: "admin", Name: "Jane"}, a)
+	}
+	{
+		var a Account
+		assert.Nil(t, New(&a).FromNestedMap(data, WithReadonlyTag("", "")))
+		assert.Equal(t, 17, a.ID)
+	}
+}
+
+func TestBindReadonlyEmbedded(t *testing.T) {
+	t.Parallel()
+	data := map[string]interface{}{"Title": "Doc", "CreatedBy": "attacker"}
+	{
+		var d AuditedDoc
+		assert.Nil(t, New(&d).FromNestedMap(data))
+		assert.Equal(t, AuditedDoc{Title: "Doc"}, d)
+	}
+	{
+		var d AuditedDoc
+		err := New(&d).FromNestedMap(data, WithStrictReadonly())
+		assert.NotNil(t, err)
+		assert.Equal(t, "field CreatedBy: read-only", err.Error())
+		assert.Equal(t, AuditedDoc{Title: "Doc"}, d)
+	}
+	{
+		var d AuditedDoc
+		assert.Nil(t, New(&d).UnmarshalJSONInto([]byte(`{"title": "Doc", "created_by": "attacker"}`)))
+		assert.Equal(t, AuditedDoc{Title: "Doc"}, d)
+	}
+}
+
+func TestToMap(t *testing.T) {
+	t.Parallel()
+	{
+		m, err := New(Person{Name: "John", Address: Address{Street: "Main", Number: 7}}).ToMap()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"Name": "John", "Street": "Main", "Number": 7}, m)
+	}
+	{
+		// Outer fields have precedence:
+		m, err := New(&Company{Address: Address{Number: 1}, Number: 2}).ToMap()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"Street": "", "Number": 2}, m)
+	}
+	{
+		u := JSONUser{ID: 1, Name: "John", Secret: "secret", Address: &Address{Street: "Main"}}
+		m, err := New(u).ToMap(WithTagKey("json"))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), m["id"])
+		assert.Equal(t, "John", m["name"])
+		assert.Equal(t, Address{Street: "Main"}, m["address"])
+		assert.Equal(t, "", m["Untaged"])
+		_, found := m["Secret"]
+		assert.False(t, found)
+
+		u.Address = nil
+		m, err = New(u).ToMap()
+		assert.Nil(t, err)
+		assert.Nil(t, m["Address"])
+		assert.Equal(t, "secret", m["Secret"])
+	}
+	{
+		m, err := New(tmp.TestStruct{}).ToMap()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(m))
+	}
+	_, err := New(1).ToMap()
+	assert.NotNil(t, err)
+	_, err = New((*Person)(nil)).ToMap()
+	assert.NotNil(t, err)
+}
+
+func TestFromNestedMapFlat(t *testing.T) {
+	t.Parallel()
+	{
+		var u JSONUser
+		err := New(&u).FromNestedMap(map[string]interface{}{
+			"id":      float64(17),
+			"name":    "John",
+			"age":     "30",
+			"address": Address{Street: "Main"},
+			"unknown": 1,
+		}, WithTagKey("json"))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(17), u.ID)
+		assert.Equal(t, "John", u.Name)
+		assert.Equal(t, 30, u.Age)
+		assert.Equal(t, &Address{Street: "Main"}, u.Address)
+	}
+	{
+		// Round trip:
+		original := JSONUser{ID: 1, Name: "John", Age: 2, Secret: "s", Untaged: "u", Address: &Address{Number: 3}}
+		m, err := New(original).ToMap()
+		assert.Nil(t, err)
+		var u JSONUser
+		assert.Nil(t, New(&u).FromNestedMap(m))
+		assert.Equal(t, original, u)
+	}
+	{
+		var u JSONUser
+		err := New(&u).FromNestedMap(map[string]interface{}{"ID": 1.5, "Name": "John", "Age": "x"})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "field ID:")
+		assert.Contains(t, err.Error(), "field Age:")
+		assert.Equal(t, "John", u.Name)
+	}
+	{
+		u := JSONUser{Name: "John"}
+		assert.NotNil(t, New(u).FromNestedMap(map[string]interface{}{"Name": "Jane"}))
+		assert.Equal(t, "John", u.Name)
+	}
+}
+
+func TestTagStructured(t *testing.T) {
+	t.Parallel()
+	obj := New(&JSONUser{})
+	for _, data := range []struct {
+		field   string
+		name    string
+		options []string
+	}{
+		{field: "ID", name: "id", options: []string{}},
+		{field: "Name", name: "name", options: []string{"omitempty"}},
+		{field: "Secret", name: "-"},
+		{field: "Untaged", name: "Untaged", options: []string{}},
+	} {
+		name, options, err := obj.Field(data.field).TagStructured("json")
+		assert.Nil(t, err)
+		assert.Equal(t, data.name, name, data.field)
+		assert.Equal(t, data.options, options, data.field)
+	}
+
+	type WithOptions struct {
+		Count int `json:",string,omitempty"`
+	}
+	name, options, err := New(WithOptions{}).Field("Count").TagStructured("json")
+	assert.Nil(t, err)
+	assert.Equal(t, "Count", name)
+	assert.Equal(t, []string{"string", "omitempty"}, options)
+
+	_, _, err = obj.Field("Invalid").TagStructured("json")
+	assert.NotNil(t, err)
+}
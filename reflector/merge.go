@@ -0,0 +1,125 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeOption configures Merge.
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	overwriteWithZero bool
+	replaceNested     bool
+}
+
+func newMergeOptions(opts []MergeOption) mergeOptions {
+	var res mergeOptions
+	for _, opt := range opts {
+		opt(&res)
+	}
+	return res
+}
+
+// WithOverwriteZero makes Merge copy zero source values, too (by default they are skipped).
+func WithOverwriteZero() MergeOption {
+	return func(mo *mergeOptions) {
+		mo.overwriteWithZero = true
+	}
+}
+
+// WithReplaceNested makes Merge assign nested structs wholesale, instead of merging them field by field.
+func WithReplaceNested() MergeOption {
+	return func(mo *mergeOptions) {
+		mo.replaceNested = true
+	}
+}
+
+// Merge copies non-zero exported fields from src into the fields with the same names in this object. Nested
+// (and embedded, also unexported embedded) structs of the same type are merged recursively, fields missing in the
+// destination are ignored. Type mismatches are collected and returned as one error, other fields are merged anyway.
+//
+// The object must be a pointer to struct, src can be a struct or a pointer to struct.
+func (o *Obj) Merge(src *Obj, opts ...MergeOption) error {
+	if err := o.assertBindable(); err != nil {
+		return err
+	}
+	if !src.IsStructOrPtrToStruct() || !src.fieldsValue.IsValid() {
+		return fmt.Errorf("cannot merge from %s, struct required", src.String())
+	}
+	defer o.lock()()
+	if src.mu != o.mu {
+		defer src.rlock()()
+	}
+
+	m := merger{mergeOptions: newMergeOptions(opts)}
+	m.merge(o.fieldsValue, src.fieldsValue, "")
+	if len(m.errs) > 0 {
+		return m.errs
+	}
+	return nil
+}
+
+type merger struct {
+	mergeOptions
+
+	errs errorList
+}
+
+func (m *merger) merge(dst, src reflect.Value, prefix string) {
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Type().Field(i)
+		if field.PkgPath != "" && !isUnexportedEmbed(field) {
+			continue
+		}
+		// Only direct fields, embedded structs are merged by their own names:
+		dstField, found := dst.Type().FieldByName(field.Name)
+		if !found || len(dstField.Index) != 1 {
+			continue
+		}
+		if field.PkgPath != "" {
+			if dstField.Type == field.Type {
+				m.mergeUnexportedEmbed(dst.Field(dstField.Index[0]), src.Field(i), prefix+field.Name)
+			}
+			continue
+		}
+		m.mergeField(dst.Field(dstField.Index[0]), src.Field(i), prefix+field.Name)
+	}
+}
+
+// mergeUnexportedEmbed merges exported fields of an unexported embedded struct (or pointer to struct), which
+// can't be assigned as a whole.
+func (m *merger) mergeUnexportedEmbed(dst, src reflect.Value, path string) {
+	if src.Kind() == reflect.Ptr {
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			m.errs = append(m.errs, fmt.Errorf("field %s: cannot allocate unexported embedded %s", path, dst.Type().String()))
+			return
+		}
+		dst, src = dst.Elem(), src.Elem()
+	}
+	m.merge(dst, src, path+".")
+}
+
+func (m *merger) mergeField(dst, src reflect.Value, path string) {
+	if !m.replaceNested && dst.Type() == src.Type() {
+		switch {
+		case src.Kind() == reflect.Struct && hasExportedFields(src.Type()):
+			m.merge(dst, src, path+".")
+			return
+		case src.Kind() == reflect.Ptr && src.Type().Elem().Kind() == reflect.Struct && !src.IsNil() && !dst.IsNil():
+			m.merge(dst.Elem(), src.Elem(), path+".")
+			return
+		}
+	}
+	if !m.overwriteWithZero && isZeroValue(src) {
+		return
+	}
+	if !src.Type().AssignableTo(dst.Type()) {
+		m.errs = append(m.errs, fmt.Errorf("field %s: cannot use %s as %s", path, src.Type().String(), dst.Type().String()))
+		return
+	}
+	dst.Set(src)
+}
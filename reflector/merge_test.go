@@ -0,0 +1,93 @@
+package reflector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MergeConfig struct {
+	Name    string
+	Port    int
+	Debug   bool
+	Address Address
+	Limits  *MergeLimits
+	Tags    []string
+	secret  string
+}
+
+type MergeLimits struct {
+	Max int
+	Min int
+}
+
+type OtherMergeConfig struct {
+	Name    int
+	Port    int
+	Missing string
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	{
+		dst := MergeConfig{Name: "base", Port: 80, Address: Address{Street: "Main", Number: 1}, Limits: &MergeLimits{Max: 10, Min: 1}, secret: "s"}
+		src := MergeConfig{Port: 8080, Debug: true, Address: Address{Number: 2}, Limits: &MergeLimits{Max: 20}, secret: "x"}
+		assert.Nil(t, New(&dst).Merge(New(src)))
+		assert.Equal(t, MergeConfig{Name: "base", Port: 8080, Debug: true, Address: Address{Street: "Main", Number: 2}, Limits: &MergeLimits{Max: 20, Min: 1}, secret: "s"}, dst)
+	}
+	{
+		dst := MergeConfig{Name: "base", Port: 80, Address: Address{Street: "Main", Number: 1}}
+		src := MergeConfig{Port: 8080, Address: Address{Number: 2}}
+		assert.Nil(t, New(&dst).Merge(New(&src), WithReplaceNested()))
+		assert.Equal(t, MergeConfig{Name: "base", Port: 8080, Address: Address{Number: 2}}, dst)
+	}
+	{
+		dst := MergeConfig{Name: "base", Port: 80, Tags: []string{"a"}, Address: Address{Street: "Main"}}
+		src := MergeConfig{Port: 8080}
+		assert.Nil(t, New(&dst).Merge(New(src), WithOverwriteZero()))
+		assert.Equal(t, MergeConfig{Port: 8080}, dst)
+	}
+	{
+		// Nil destination pointer gets the source pointer:
+		dst := MergeConfig{}
+		src := MergeConfig{Limits: &MergeLimits{Max: 1}}
+		assert.Nil(t, New(&dst).Merge(New(src)))
+		assert.Equal(t, &MergeLimits{Max: 1}, dst.Limits)
+	}
+}
+
+func TestMergeUnexportedEmbedded(t *testing.T) {
+	t.Parallel()
+	{
+		dst := WithHiddenBase{Name: "dst", hiddenBase: hiddenBase{Label: "dst", Count: 1}, hiddenExtra: &hiddenExtra{}}
+		src := WithHiddenBase{hiddenBase: hiddenBase{Count: 2}, hiddenExtra: &hiddenExtra{Note: "src"}}
+		assert.Nil(t, New(&dst).Merge(New(src)))
+		assert.Equal(t, WithHiddenBase{Name: "dst", hiddenBase: hiddenBase{Label: "dst", Count: 2}, hiddenExtra: &hiddenExtra{Note: "src"}}, dst)
+	}
+	{
+		// Unexported nil pointers can't be allocated:
+		var dst WithHiddenBase
+		src := WithHiddenBase{Name: "src", hiddenExtra: &hiddenExtra{Note: "src"}}
+		err := New(&dst).Merge(New(src))
+		assert.NotNil(t, err)
+		assert.Equal(t, "field hiddenExtra: cannot allocate unexported embedded *reflector.hiddenExtra", err.Error())
+		assert.Equal(t, "src", dst.Name)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	t.Parallel()
+	dst := MergeConfig{Name: "base"}
+	err := New(&dst).Merge(New(OtherMergeConfig{Name: 1, Port: 8080, Missing: "m"}))
+	assert.NotNil(t, err)
+	assert.Equal(t, "field Name: cannot use int as string", err.Error())
+	// Other fields are merged anyway:
+	assert.Equal(t, MergeConfig{Name: "base", Port: 8080}, dst)
+
+	assert.NotNil(t, New(dst).Merge(New(MergeConfig{})))
+	assert.NotNil(t, New(&dst).Merge(New(nil)))
+	assert.NotNil(t, New(&dst).Merge(New(1)))
+
+	obj := New(&dst).WithMutex()
+	assert.Nil(t, obj.Merge(obj))
+}
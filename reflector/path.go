@@ -0,0 +1,202 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// setPath sets the value at a dotted path, starting from the (settable) root value.
+//
+// Path segments are struct field names, map keys or slice/array indexes. Nil pointers, maps and too short
+// slices along the way are allocated.
+func setPath(root reflect.Value, path string, value interface{}) error {
+	segments := strings.Split(path, ".")
+	cur := root
+	for n, segment := range segments {
+		var err error
+		if cur, err = allocatePtr(cur); err != nil {
+			return err
+		}
+		if cur.Kind() == reflect.Map {
+			return setMapPath(cur, segment, segments[n+1:], value)
+		}
+		if cur, err = pathStep(cur, segment, true); err != nil {
+			return err
+		}
+	}
+	return assignConverted(cur, value)
+}
+
+// resolvePath returns the value at a dotted path (see setPath) and its parent (the struct, slice, array or map
+// containing it). If allocate is true, nil pointers and too short slices along the way are allocated (if
+// settable), otherwise they are errors.
+func resolvePath(root reflect.Value, path string, allocate bool) (parent, value reflect.Value, err error) {
+	value = root
+	for _, segment := range strings.Split(path, ".") {
+		if allocate {
+			value, err = allocatePtr(value)
+		} else {
+			value, err = derefPtr(value)
+		}
+		if err != nil {
+			return
+		}
+		parent = value
+		if value, err = pathStep(value, segment, allocate); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// pathStep resolves a single path segment in a struct, map, slice or array. Map values are not addressable.
+func pathStep(v reflect.Value, segment string, allocate bool) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Struct:
+		structField, found := v.Type().FieldByName(segment)
+		if !found {
+			return v, fmt.Errorf("no field %s in %s", segment, v.Type().String())
+		}
+		if structField.PkgPath != "" {
+			return v, fmt.Errorf("unexported field %s in %s", segment, v.Type().String())
+		}
+		if allocate {
+			return fieldByIndexAlloc(v, structField.Index)
+		}
+		return v.FieldByIndexErr(structField.Index)
+	case reflect.Map:
+		key, err := convertValue(segment, v.Type().Key())
+		if err != nil {
+			return v, err
+		}
+		elem := v.MapIndex(key)
+		if !elem.IsValid() {
+			return v, fmt.Errorf("no key %s in %s", segment, v.Type().String())
+		}
+		return elem, nil
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 {
+			return v, fmt.Errorf("invalid index %s for %s", segment, v.Type().String())
+		}
+		if index >= v.Len() {
+			if !allocate || v.Kind() == reflect.Array || !v.CanSet() {
+				return v, fmt.Errorf("index %d out of range for %s", index, v.Type().String())
+			}
+			grown := reflect.MakeSlice(v.Type(), index+1, index+1)
+			reflect.Copy(grown, v)
+			v.Set(grown)
+		}
+		return v.Index(index), nil
+	}
+	return v, fmt.Errorf("cannot resolve %s in %s", segment, v.Type().String())
+}
+
+func setMapPath(m reflect.Value, segment string, rest []string, value interface{}) error {
+	if m.IsNil() {
+		if !m.CanSet() {
+			return fmt.Errorf("cannot allocate nil %s", m.Type().String())
+		}
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	key, err := convertValue(segment, m.Type().Key())
+	if err != nil {
+		return err
+	}
+	// Map elements are not addressable, so set a copy and store it back:
+	elem := reflect.New(m.Type().Elem()).Elem()
+	if existing := m.MapIndex(key); existing.IsValid() {
+		elem.Set(existing)
+	}
+	if len(rest) == 0 {
+		err = assignConverted(elem, value)
+	} else {
+		err = setPath(elem, strings.Join(rest, "."), value)
+	}
+	if err != nil {
+		return err
+	}
+	m.SetMapIndex(key, elem)
+	return nil
+}
+
+// allocatePtr dereferences pointers, allocating nil pointers when possible.
+func allocatePtr(v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !v.CanSet() {
+				return v, fmt.Errorf("cannot allocate nil %s", v.Type().String())
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v, nil
+}
+
+// derefPtr dereferences pointers, nil pointers are errors.
+func derefPtr(v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return v, fmt.Errorf("nil %s", v.Type().String())
+		}
+		v = v.Elem()
+	}
+	return v, nil
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex, but allocates nil embedded pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for n, i := range index {
+		if n > 0 {
+			var err error
+			if v, err = allocatePtr(v); err != nil {
+				return v, err
+			}
+		}
+		v = v.Field(i)
+	}
+	return v, nil
+}
+
+func assignConverted(v reflect.Value, value interface{}) error {
+	if !v.CanSet() {
+		return fmt.Errorf("%s not settable", v.Type().String())
+	}
+	converted, err := convertValue(value, v.Type())
+	if err != nil {
+		return err
+	}
+	v.Set(converted)
+	return nil
+}
+
+// BuildFromPaths allocates a new value of type t and sets values by their dotted paths, for example
+// "Address.Street", "Tags.0" or "Attributes.color". Intermediate pointers, maps and slices are allocated
+// as needed, and values are converted to the target types when possible.
+func BuildFromPaths(t reflect.Type, values map[string]interface{}) (interface{}, error) {
+	if t == nil {
+		return nil, fmt.Errorf("invalid nil type")
+	}
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	res := reflect.New(t).Elem()
+	var errs errorList
+	for _, path := range paths {
+		if err := setPath(res, path, values[path]); err != nil {
+			errs = append(errs, fmt.Errorf("path %s: %w", path, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return res.Interface(), nil
+}
@@ -0,0 +1,228 @@
+package reflector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Building struct {
+	Name       string
+	Floors     int
+	Address    *Address
+	Tags       []string
+	Corners    [2]Point
+	Attributes map[string]string
+	Owners     map[string]Person
+	*Point
+}
+
+func TestBuildFromPaths(t *testing.T) {
+	t.Parallel()
+	res, err := BuildFromPaths(reflect.TypeOf(Building{}), map[string]interface{}{
+		"Name":               "Tower",
+		"Floors":             float64(10),
+		"Address.Street":     "Main",
+		"Tags.1":             "b",
+		"Corners.1.X":        3,
+		"Attributes.color":   "red",
+		"Owners.john.Name":   "John",
+		"Owners.john.Number": 7,
+		"X":                  5,
+	})
+	assert.Nil(t, err)
+	b := res.(Building)
+	assert.Equal(t, "Tower", b.Name)
+	assert.Equal(t, 10, b.Floors)
+	assert.Equal(t, &Address{Street: "Main"}, b.Address)
+	assert.Equal(t, []string{"", "b"}, b.Tags)
+	assert.Equal(t, [2]Point{{}, {X: 3}}, b.Corners)
+	assert.Equal(t, map[string]string{"color": "red"}, b.Attributes)
+	assert.Equal(t, map[string]Person{"john": {Name: "John", Address: Address{Number: 7}}}, b.Owners)
+	assert.Equal(t, &Point{X: 5}, b.Point)
+
+	res, err = BuildFromPaths(reflect.TypeOf(&Address{}), map[string]interface{}{"Street": "Main"})
+	assert.Nil(t, err)
+	assert.Equal(t, &Address{Street: "Main"}, res)
+}
+
+func TestBuildFromPathsErrors(t *testing.T) {
+	t.Parallel()
+	_, err := BuildFromPaths(reflect.TypeOf(Building{}), map[string]interface{}{
+		"Invalid":      1,
+		"Name.Invalid": 1,
+		"Corners.2.X":  1,
+		"Tags.x":       "a",
+		"Floors":       "ten",
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "path Corners.2.X: index 2 out of range")
+	assert.Contains(t, err.Error(), "path Floors:")
+	assert.Contains(t, err.Error(), "path Invalid: no field Invalid")
+	assert.Contains(t, err.Error(), "path Name.Invalid:")
+	assert.Contains(t, err.Error(), "path Tags.x: invalid index")
+
+	_, err = BuildFromPaths(nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestFieldByPath(t *testing.T) {
+	t.Parallel()
+	{
+		c := Company{Address: Address{Street: "Main"}, Number: 7}
+		obj := New(&c)
+		street := obj.FieldByPath("Address.Street")
+		assert.True(t, street.IsValid())
+		assert.Equal(t, "Street", street.Name())
+		value, err := street.Get()
+		assert.Nil(t, err)
+		assert.Equal(t, "Main", value)
+		assert.Nil(t, street.Set("Other"))
+		assert.Equal(t, "Other", c.Street)
+
+		// Single segment works like Field():
+		value, err = obj.FieldByPath("Number").Get()
+		assert.Nil(t, err)
+		assert.Equal(t, 7, value)
+	}
+	{
+		var b Building
+		obj := New(&b)
+		// Intermediate nil pointers are allocated:
+		assert.Nil(t, obj.FieldByPath("Address.Number").Set(17))
+		assert.Equal(t, &Address{Number: 17}, b.Address)
+		assert.Nil(t, obj.FieldByPath("Point.X").Set(1))
+		assert.Equal(t, &Point{X: 1}, b.Point)
+	}
+	{
+		// Not addressable, nil pointers can't be allocated:
+		var b Building
+		assert.False(t, New(b).FieldByPath("Address.Number").IsValid())
+		assert.Nil(t, b.Address)
+
+		b.Address = &Address{Number: 3}
+		value, err := New(b).FieldByPath("Address.Number").Get()
+		assert.Nil(t, err)
+		assert.Equal(t, 3, value)
+	}
+	{
+		obj := New(&Building{})
+		for _, path := range []string{"Invalid", "Invalid.Street", "Address.Invalid", "Name.Street", "Tags.X", "", "Address."} {
+			assert.False(t, obj.FieldByPath(path).IsValid(), path)
+		}
+		assert.False(t, New(nil).FieldByPath("Address.Street").IsValid())
+	}
+}
+
+func TestSetByPath(t *testing.T) {
+	t.Parallel()
+	{
+		var p Person
+		obj := New(&p)
+		// Embedded struct by its field name:
+		assert.Nil(t, obj.SetByPath("Address.Number", 42))
+		assert.Equal(t, 42, p.Number)
+		assert.Nil(t, obj.SetByPath("Name", "John"))
+		assert.Equal(t, "John", p.Name)
+
+		// Values are converted:
+		assert.Nil(t, obj.SetByPath("Address.Number", "43"))
+		assert.Equal(t, 43, p.Number)
+
+		err := obj.SetByPath("Address.Number", "x")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "path Address.Number:")
+
+		err = obj.SetByPath("Address.Invalid", 1)
+		assert.NotNil(t, err)
+		assert.Equal(t, "path Address.Invalid: no field Invalid in reflector.Address", err.Error())
+	}
+	{
+		var b Building
+		obj := New(&b)
+		assert.Nil(t, obj.SetByPath("Address.Street", "Main"))
+		assert.Equal(t, &Address{Street: "Main"}, b.Address)
+		// Slice indexes and map keys:
+		assert.Nil(t, obj.SetByPath("Tags.1", "b"))
+		assert.Equal(t, []string{"", "b"}, b.Tags)
+		assert.Nil(t, obj.SetByPath("Owners.john.Name", "John"))
+		assert.Equal(t, map[string]Person{"john": {Name: "John"}}, b.Owners)
+		assert.Nil(t, obj.SetByPath("Corners.1.X", 3))
+		assert.Equal(t, 3, b.Corners[1].X)
+	}
+	{
+		var p Person
+		err := New(p).SetByPath("Address.Number", 42)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "pointer required")
+		assert.NotNil(t, New((*Person)(nil)).SetByPath("Name", "John"))
+	}
+	{
+		type WithPtrToInt struct {
+			Ptr *int
+		}
+		err := New(&WithPtrToInt{}).SetByPath("Ptr.X", 1)
+		assert.NotNil(t, err)
+		assert.Equal(t, "path Ptr.X: cannot resolve X in int", err.Error())
+	}
+}
+
+func TestPathsConsistency(t *testing.T) {
+	t.Parallel()
+	values := map[string]interface{}{
+		"Name":             "Tower",
+		"Address.Street":   "Main",
+		"Tags.1":           "b",
+		"Corners.1.X":      3,
+		"Attributes.color": "red",
+		"Owners.john.Name": "John",
+		"X":                5,
+	}
+	built, err := BuildFromPaths(reflect.TypeOf(Building{}), values)
+	assert.Nil(t, err)
+
+	var b Building
+	obj := New(&b)
+	for path, value := range values {
+		assert.Nil(t, obj.SetByPath(path, value), path)
+	}
+	assert.Equal(t, built, b)
+
+	for path, value := range values {
+		got, err := obj.FieldByPath(path).Get()
+		assert.Nil(t, err, path)
+		assert.Equal(t, value, got, path)
+	}
+	// Map values are not addressable:
+	assert.False(t, obj.FieldByPath("Owners.john.Name").IsSettable())
+	assert.True(t, obj.FieldByPath("Tags.1").IsSettable())
+}
+
+func TestFieldWithPath(t *testing.T) {
+	t.Parallel()
+	c := Company{Address: Address{Number: 1}, Number: 2}
+	obj := New(&c)
+
+	// The shallowest one:
+	value, err := obj.Field("Number").Get()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, value)
+
+	// The shadowed one:
+	field := obj.Field("Address.Number")
+	assert.True(t, field.IsValid())
+	value, err = field.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, value)
+	assert.Nil(t, field.Set(10))
+	assert.Equal(t, Company{Address: Address{Number: 10}, Number: 2}, c)
+
+	// Nil pointers are not allocated:
+	var b Building
+	assert.False(t, New(&b).Field("Address.Street").IsValid())
+	assert.Nil(t, b.Address)
+
+	assert.False(t, obj.Field("Address.Invalid").IsValid())
+	assert.False(t, obj.Field("Address.").IsValid())
+}
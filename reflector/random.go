@@ -0,0 +1,105 @@
+package reflector
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+const (
+	// Pointers, slices and maps are filled only up to this depth (to avoid endless recursive types):
+	randomMaxDepth = 5
+	// Slices and maps get between 1 and randomMaxLen elements:
+	randomMaxLen = 3
+
+	randomLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// Randomize fills every settable (exported) field with a random value appropriate for its kind, for example
+// to generate test fixtures. Nested structs are filled recursively, slices and maps get a few random elements
+// and pointers are allocated. Interfaces, channels and funcs are left untouched.
+// The object must be a pointer to struct, and r must not be nil.
+func (o *Obj) Randomize(r *rand.Rand) error {
+	if err := o.assertBindable(); err != nil {
+		return err
+	}
+	if r == nil {
+		return fmt.Errorf("cannot randomize %s, nil random source", o.String())
+	}
+	randomizeValue(r, o.fieldsValue, 0)
+	return nil
+}
+
+func randomizeValue(r *rand.Rand, v reflect.Value, depth int) {
+	if !v.CanSet() {
+		return
+	}
+
+	ty := v.Type()
+	switch {
+	case ty == timeType:
+		v.Set(reflect.ValueOf(time.Unix(r.Int63n(1<<32), 0).UTC()))
+		return
+	case isIntKind(ty.Kind()):
+		v.SetInt(int64(r.Uint64()) >> (64 - ty.Bits()))
+		return
+	case isUintKind(ty.Kind()):
+		v.SetUint(r.Uint64() >> (64 - ty.Bits()))
+		return
+	case isFloatKind(ty.Kind()):
+		v.SetFloat(r.NormFloat64())
+		return
+	}
+
+	switch ty.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(complex(r.NormFloat64(), r.NormFloat64()))
+	case reflect.String:
+		byts := make([]byte, 1+r.Intn(10))
+		for n := range byts {
+			byts[n] = randomLetters[r.Intn(len(randomLetters))]
+		}
+		v.SetString(string(byts))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			randomizeValue(r, v.Field(i), depth)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			randomizeValue(r, v.Index(i), depth)
+		}
+	case reflect.Slice:
+		if depth >= randomMaxDepth {
+			return
+		}
+		n := 1 + r.Intn(randomMaxLen)
+		slice := reflect.MakeSlice(ty, n, n)
+		for i := 0; i < n; i++ {
+			randomizeValue(r, slice.Index(i), depth+1)
+		}
+		v.Set(slice)
+	case reflect.Map:
+		if depth >= randomMaxDepth {
+			return
+		}
+		m := reflect.MakeMap(ty)
+		for i := 1 + r.Intn(randomMaxLen); i > 0; i-- {
+			key := reflect.New(ty.Key()).Elem()
+			randomizeValue(r, key, depth+1)
+			value := reflect.New(ty.Elem()).Elem()
+			randomizeValue(r, value, depth+1)
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	case reflect.Ptr:
+		if depth >= randomMaxDepth {
+			return
+		}
+		ptr := reflect.New(ty.Elem())
+		randomizeValue(r, ptr.Elem(), depth+1)
+		v.Set(ptr)
+	}
+}
@@ -0,0 +1,63 @@
+package reflector
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type RandomFixture struct {
+	Bool    bool
+	Int8    int8
+	Uint16  uint16
+	Float   float64
+	Complex complex64
+	String  string
+	Bytes   []byte
+	Array   [2]int
+	Map     map[string]int
+	Shape   Shape
+	Points  []*Point
+	Next    *RandomFixture
+	Any     interface{}
+	private int
+}
+
+func TestRandomize(t *testing.T) {
+	t.Parallel()
+	var f RandomFixture
+	r := rand.New(rand.NewSource(1))
+	assert.Nil(t, New(&f).Randomize(r))
+
+	assert.NotEmpty(t, f.String)
+	assert.NotEmpty(t, f.Bytes)
+	assert.NotEmpty(t, f.Map)
+	assert.NotEmpty(t, f.Shape.Name)
+	assert.NotEmpty(t, f.Shape.Street)
+	assert.False(t, f.Shape.Created.IsZero())
+	assert.NotEmpty(t, f.Points)
+	assert.NotNil(t, f.Points[0])
+	assert.NotNil(t, f.Next)
+	assert.NotEqual(t, 0.0, f.Float)
+	assert.Nil(t, f.Any)
+	assert.Equal(t, 0, f.private)
+
+	// Recursive types are filled only up to a max depth:
+	depth := 0
+	for next := f.Next; next != nil; next = next.Next {
+		depth++
+	}
+	assert.Equal(t, randomMaxDepth, depth)
+
+	// Same seed, same values:
+	var f2 RandomFixture
+	assert.Nil(t, New(&f2).Randomize(rand.New(rand.NewSource(1))))
+	assert.Equal(t, f, f2)
+
+	assert.NotNil(t, New(f).Randomize(r))
+
+	err := New(&f2).Randomize(nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "cannot randomize *reflector.RandomFixture, nil random source", err.Error())
+}
@@ -2,9 +2,13 @@ package reflector
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 type fieldListingType int
@@ -16,20 +20,26 @@ const (
 	fieldsNoFlattenAnonymous
 )
 
-var (
-	metadataCache      map[reflect.Type]ObjMetadata
-	metadataCacheMutex sync.RWMutex
-)
+var timeType = reflect.TypeOf(time.Time{})
 
-func init() {
-	metadataCache = map[reflect.Type]ObjMetadata{}
-}
+// metadataCache contains ObjMetadata values keyed by reflect.Type.
+var metadataCache sync.Map
 
-func updateCache(ty reflect.Type, o *Obj) {
-	metadataCacheMutex.Lock()
-	defer metadataCacheMutex.Unlock()
+func getMetadata(ty reflect.Type) ObjMetadata {
+	if metadata, found := metadataCache.Load(ty); found {
+		return metadata.(ObjMetadata)
+	}
+	metadata, _ := metadataCache.LoadOrStore(ty, *newObjMetadata(ty))
+	return metadata.(ObjMetadata)
+}
 
-	metadataCache[ty] = o.ObjMetadata
+// ClearTypeCache removes all cached type metadata.
+// Mostly useful in tests, metadata is always recomputed on the next New()/NewFromType().
+func ClearTypeCache() {
+	metadataCache.Range(func(key, _ interface{}) bool {
+		metadataCache.Delete(key)
+		return true
+	})
 }
 
 // ObjMetadata contains data which is always unique per Type.
@@ -49,6 +59,8 @@ type ObjMetadata struct {
 	fieldNamesAnonymous          []string
 	fieldNamesFlattenAnonymous   []string
 	fieldNamesNoFlattenAnonymous []string
+	// Names of fields declared multiple times (see FindDoubleFields):
+	doubleFieldNames []string
 
 	methods     map[string]ObjMethodMetadata
 	methodNames []string
@@ -79,6 +91,7 @@ func newObjMetadata(ty reflect.Type) *ObjMetadata {
 	res.fieldNamesAnonymous = res.getFields(res.objType, fieldsAnonymous)
 	res.fieldNamesFlattenAnonymous = res.getFields(res.objType, fieldsFlattenAnonymous)
 	res.fieldNamesNoFlattenAnonymous = res.getFields(res.objType, fieldsNoFlattenAnonymous)
+	res.doubleFieldNames = findDoubleFieldNames(allFields)
 
 	res.methods = map[string]ObjMethodMetadata{}
 	res.methodNames = []string{}
@@ -103,20 +116,21 @@ func (om *ObjMetadata) IsStructOrPtrToStruct() bool {
 	return om.isStruct || om.isPtrToStruct
 }
 
-func (om *ObjMetadata) appendFields(fields []string, field reflect.StructField, listingType fieldListingType) []string {
-	k := field.Type.Kind()
+func (om *ObjMetadata) appendFields(fields []string, field reflect.StructField, listingType fieldListingType, visited map[reflect.Type]bool) []string {
+	// Embedded structs and pointers to structs are flattened:
+	flatten := field.Anonymous && isStructOrPtrToStruct(field.Type)
 	if listingType == fieldsAnonymous {
 		if field.Anonymous {
 			fields = append(fields, field.Name)
 		}
 	} else if listingType == fieldsAll {
 		fields = append(fields, field.Name)
-		if k == reflect.Struct && field.Anonymous {
-			fields = append(fields, om.getFields(field.Type, listingType)...)
+		if flatten {
+			fields = append(fields, om.collectFields(field.Type, listingType, visited)...)
 		}
 	} else {
-		if listingType == fieldsFlattenAnonymous && k == reflect.Struct && field.Anonymous {
-			fields = append(fields, om.getFields(field.Type, listingType)...)
+		if listingType == fieldsFlattenAnonymous && flatten {
+			fields = append(fields, om.collectFields(field.Type, listingType, visited)...)
 		} else {
 			fields = append(fields, field.Name)
 		}
@@ -125,24 +139,36 @@ func (om *ObjMetadata) appendFields(fields []string, field reflect.StructField,
 }
 
 func (om *ObjMetadata) getFields(ty reflect.Type, listingType fieldListingType) []string {
+	return om.collectFields(ty, listingType, map[reflect.Type]bool{})
+}
+
+// collectFields lists fields of the struct, visited contains structs currently being listed (embedded pointers
+// can be recursive).
+func (om *ObjMetadata) collectFields(ty reflect.Type, listingType fieldListingType, visited map[reflect.Type]bool) []string {
 	var fields []string
 
 	if ty.Kind() == reflect.Ptr {
 		ty = ty.Elem()
 	}
 
-	if ty.Kind() != reflect.Struct {
+	if ty.Kind() != reflect.Struct || visited[ty] {
 		return fields // No need to populate nonstructs
 	}
+	visited[ty] = true
+	defer delete(visited, ty)
 
 	for i := 0; i < ty.NumField(); i++ {
 		f := ty.Field(i)
-		fields = om.appendFields(fields, f, listingType)
+		fields = om.appendFields(fields, f, listingType, visited)
 	}
 
 	return fields
 }
 
+func isStructOrPtrToStruct(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Struct || (ty.Kind() == reflect.Ptr && ty.Elem().Kind() == reflect.Struct)
+}
+
 // ObjFieldMetadata contains data which is always unique per Type/Field.
 type ObjFieldMetadata struct {
 	name string
@@ -208,15 +234,26 @@ func newObjMethodMetadata(ty reflect.Type, name string, objMetadata *ObjMetadata
 
 // Obj is a wrapper for golang values which need to be reflected.
 // The value can be of any kind and any type.
+//
+// Type metadata is computed eagerly in New (once per type) and never changed later, so New and read-only
+// methods (Fields, Methods, Field, Method, ...) are safe to call concurrently, also on a shared *Obj. Setting
+// values must be synchronized by the caller (WithMutex guards only ObjField.Set and ObjField.Get).
 type Obj struct {
 	iface interface{}
 	// Value used to work with fields. The only special case is when iface is a pointer to a struct, in
 	// that case this is the value of that struct:
 	fieldsValue reflect.Value
+	// If the object is a pointer obtained from an addressable value (for example a struct field), the
+	// settable pointer itself (see EnsureAllocated):
+	ptrValue reflect.Value
+	// Optional, guards ObjField Set/Get (see WithMutex):
+	mu *sync.RWMutex
 	ObjMetadata
 }
 
 // NewFromType creates a new Obj but using reflect.Type.
+// The object wraps a pointer to a newly allocated zero value of that type,
+// metadata is computed only once per type and shared by all objects created this way.
 func NewFromType(ty reflect.Type) *Obj {
 	if ty == nil {
 		return New(nil)
@@ -226,24 +263,127 @@ func NewFromType(ty reflect.Type) *Obj {
 
 // New initializes a new Obj wrapper.
 func New(obj interface{}) *Obj {
-	o := &Obj{iface: obj}
-
-	ty := reflect.TypeOf(obj)
-	metadataCacheMutex.RLock()
-	metadata, found := metadataCache[ty]
-	metadataCacheMutex.RUnlock()
-	if found {
-		o.ObjMetadata = metadata
-	} else {
-		o.ObjMetadata = *newObjMetadata(reflect.TypeOf(obj))
-		updateCache(ty, o)
-	}
+	o := new(Obj)
+	o.init(obj)
+	return o
+}
+
+var objPool = sync.Pool{
+	New: func() interface{} { return new(Obj) },
+}
+
+// AcquireObj is like New, but reuses objects released with ReleaseObj. Type metadata is cached anyway, so
+// only the Obj itself is recycled.
+func AcquireObj(obj interface{}) *Obj {
+	o := objPool.Get().(*Obj)
+	o.init(obj)
+	return o
+}
 
+// ReleaseObj returns the object to the pool used by AcquireObj.
+//
+// As with any sync.Pool, the object (and fields, methods or results obtained from it) must not be
+// used after it's released.
+func ReleaseObj(o *Obj) {
+	*o = Obj{}
+	objPool.Put(o)
+}
+
+func (o *Obj) init(obj interface{}) {
+	*o = Obj{iface: obj}
+	o.ObjMetadata = getMetadata(reflect.TypeOf(obj))
 	o.fieldsValue = reflect.Indirect(reflect.ValueOf(obj))
+}
+
+// NewCopy initializes a new Obj wrapper around an addressable copy of the value.
+//
+// Even when obj is not a pointer, fields of the copy are settable and pointer receiver methods can be
+// called (on the copy).
+func NewCopy(obj interface{}) *Obj {
+	v := reflect.ValueOf(obj)
+	if !v.IsValid() {
+		return New(nil)
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return newFromValue(c)
+}
+
+// currentValue returns the object's value. For addressable (non pointer) objects this is the (possibly changed)
+// addressable value, not the original one.
+func (o *Obj) currentValue() reflect.Value {
+	if o.objKind != reflect.Ptr && o.fieldsValue.CanAddr() {
+		return o.fieldsValue
+	}
+	return reflect.ValueOf(o.iface)
+}
+
+// newFromValue creates a new Obj from a reflect value. If the value is addressable (and not a pointer), the
+// object works with the value itself (not a copy) and its method set includes pointer receiver methods.
+func newFromValue(v reflect.Value) *Obj {
+	o := New(v.Interface())
+	if v.CanAddr() && v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+		o.fieldsValue = v
+		ptrMetadata := getMetadata(reflect.PtrTo(v.Type()))
+		o.methods, o.methodNames = ptrMetadata.methods, ptrMetadata.methodNames
+	}
+	if v.Kind() == reflect.Ptr && v.CanSet() {
+		o.ptrValue = v
+	}
+	return o
+}
 
+// EnsureAllocated allocates a new zero value for a nil pointer and sets the original pointer to it. The pointer
+// must be settable, i.e. the object is obtained from an addressable value (for example with AsObj from a field of
+// a pointer to struct). Does nothing if the pointer is not nil.
+func (o *Obj) EnsureAllocated() error {
+	if !o.IsPtr() {
+		return fmt.Errorf("cannot allocate %s, pointer required", o.String())
+	}
+	if !o.IsNil() {
+		return nil
+	}
+	if !o.ptrValue.CanSet() {
+		return fmt.Errorf("cannot allocate %s, not settable", o.String())
+	}
+	defer o.lock()()
+	allocated := reflect.New(o.objType.Elem())
+	o.ptrValue.Set(allocated)
+	o.iface = allocated.Interface()
+	o.fieldsValue = allocated.Elem()
+	return nil
+}
+
+// WithMutex enables locking of ObjField.Set and ObjField.Get calls on fields obtained from this object, so that
+// concurrent field readers and writers are safe at the reflector layer.
+//
+// Nothing else is guarded: bulk operations (like FromNestedMap, Filter, SortBy, NormalizeStrings, TransformLeaves
+// or EnsureAllocated) must not run concurrently with other access, and neither is direct access to the underlying
+// value (or access through another Obj wrapping it) synchronized. WithMutex must be called before the object is
+// shared between goroutines.
+func (o *Obj) WithMutex() *Obj {
+	if o.mu == nil {
+		o.mu = new(sync.RWMutex)
+	}
 	return o
 }
 
+func (o *Obj) lock() func() {
+	if o.mu == nil {
+		return func() {}
+	}
+	o.mu.Lock()
+	return o.mu.Unlock
+}
+
+func (o *Obj) rlock() func() {
+	if o.mu == nil {
+		return func() {}
+	}
+	o.mu.RLock()
+	return o.mu.RUnlock
+}
+
 // IsValid checks if the underlying objects is valid.
 // Nil is an invalid value, for example.
 func (o *Obj) IsValid() bool {
@@ -315,6 +455,20 @@ func (o *Obj) GetByIndex(index int) (value interface{}, found bool) {
 	return
 }
 
+// Index returns an Obj wrapping the element of an array or slice.
+//
+// Slice elements (and array elements, if the array is addressable, for example behind a pointer) are
+// addressable, so changes made through the returned Obj (e.g. setting fields) change the element itself.
+func (o *Obj) Index(index int) (*Obj, error) {
+	if !o.IsSettableByIndex() {
+		return nil, fmt.Errorf("cannot index %s", o.String())
+	}
+	if index < 0 || o.fieldsValue.Len() <= index {
+		return nil, fmt.Errorf("index %d out of range for %s with length %d", index, o.String(), o.fieldsValue.Len())
+	}
+	return newFromValue(o.fieldsValue.Index(index)), nil
+}
+
 // SetByIndex sets a slice value by key.
 func (o *Obj) SetByIndex(index int, val interface{}) error {
 	if index < 0 || o.Len() <= index {
@@ -330,6 +484,32 @@ func (o *Obj) SetByIndex(index int, val interface{}) error {
 	return fmt.Errorf("cannot set element %d of %s", index, o.fieldsValue.String())
 }
 
+// CallEachElement calls the method on every element of an array or slice, results are returned in elements order.
+//
+// Elements are addressed when possible (slices, or arrays behind a pointer), so pointer receiver methods
+// can be called, too.
+func (o *Obj) CallEachElement(method string, args ...interface{}) ([]*CallResult, error) {
+	switch o.fieldsValue.Kind() {
+	case reflect.Array, reflect.Slice:
+	default:
+		return nil, fmt.Errorf("cannot call %s on elements of %s", method, o.String())
+	}
+
+	res := make([]*CallResult, o.fieldsValue.Len())
+	for n := range res {
+		elem := o.fieldsValue.Index(n)
+		if elem.Kind() != reflect.Ptr && elem.Kind() != reflect.Interface && elem.CanAddr() {
+			elem = elem.Addr()
+		}
+		callResult, err := New(elem.Interface()).Method(method).Call(args...)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", n, err)
+		}
+		res[n] = callResult
+	}
+	return res, nil
+}
+
 // Keys return map keys in unspecified order.
 func (o *Obj) Keys() ([]interface{}, error) {
 	if o.IsMap() {
@@ -343,6 +523,72 @@ func (o *Obj) Keys() ([]interface{}, error) {
 	return nil, fmt.Errorf("invalid type %s", o.Type().String())
 }
 
+// MapValue returns an Obj wrapping the map value for the key. The key must be assignable to the map key type (use
+// Keys to list the existing keys).
+//
+// Map values are not addressable, so changing the returned Obj doesn't change the map (use SetByKey for that).
+func (o *Obj) MapValue(key interface{}) (*Obj, error) {
+	if !o.IsMap() {
+		return nil, fmt.Errorf("invalid type %s", o.String())
+	}
+	keyValue, err := assignableValue(key, o.fieldsValue.Type().Key())
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	defer o.rlock()()
+	value := o.fieldsValue.MapIndex(keyValue)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("key %v not found", key)
+	}
+	return New(value.Interface()), nil
+}
+
+// MapEntry is a map key/value pair (see Obj.SortedMapEntries).
+type MapEntry struct {
+	Key   *Obj
+	Value *Obj
+}
+
+// SortedMapEntries returns map entries sorted by keys using the less func.
+//
+// Map values are not addressable, so changing a Value doesn't change the map (use SetByKey for that).
+func (o *Obj) SortedMapEntries(less func(a, b interface{}) bool) ([]MapEntry, error) {
+	if !o.IsMap() {
+		return nil, fmt.Errorf("invalid type %s", o.String())
+	}
+	if less == nil {
+		return nil, fmt.Errorf("no less func")
+	}
+	keys := o.fieldsValue.MapKeys()
+	keyValues := make([]interface{}, len(keys))
+	for n := range keys {
+		keyValues[n] = keys[n].Interface()
+	}
+	sort.Sort(byLess{keys: keys, values: keyValues, less: less})
+
+	res := make([]MapEntry, len(keys))
+	for n, key := range keys {
+		res[n] = MapEntry{
+			Key:   New(keyValues[n]),
+			Value: New(o.fieldsValue.MapIndex(key).Interface()),
+		}
+	}
+	return res, nil
+}
+
+type byLess struct {
+	keys   []reflect.Value
+	values []interface{}
+	less   func(a, b interface{}) bool
+}
+
+func (bl byLess) Len() int           { return len(bl.keys) }
+func (bl byLess) Less(i, j int) bool { return bl.less(bl.values[i], bl.values[j]) }
+func (bl byLess) Swap(i, j int) {
+	bl.keys[i], bl.keys[j] = bl.keys[j], bl.keys[i]
+	bl.values[i], bl.values[j] = bl.values[j], bl.values[i]
+}
+
 // SetByKey sets a map value by key.
 func (o *Obj) SetByKey(key interface{}, val interface{}) (err error) {
 	defer func() {
@@ -406,6 +652,121 @@ func (o Obj) FieldsAnonymous() []ObjField {
 	return o.getFields(fieldsAnonymous)
 }
 
+// EmbeddedTypes returns types of anonymous (embedded) fields, as declared (for example *Address for an
+// embedded pointer). Types embedded deeper (in embedded structs) are not listed.
+func (o *Obj) EmbeddedTypes() []reflect.Type {
+	if !o.IsStructOrPtrToStruct() {
+		return nil
+	}
+	var res []reflect.Type
+	for i := 0; i < o.underlyingType.NumField(); i++ {
+		if field := o.underlyingType.Field(i); field.Anonymous {
+			res = append(res, field.Type)
+		}
+	}
+	return res
+}
+
+// EmbeddedObj returns an Obj over the value of the embedded field of the type with the given name (for
+// example "Address" or "reflector.Address"), addressable when the root object is a pointer. Directly embedded
+// types are checked first, then types embedded in those.
+func (o *Obj) EmbeddedObj(typeName string) (*Obj, error) {
+	if !o.IsStructOrPtrToStruct() || !o.fieldsValue.IsValid() {
+		return nil, fmt.Errorf("no embedded %s in %s", typeName, o.String())
+	}
+	values := []reflect.Value{o.fieldsValue}
+	visited := map[uintptr]bool{}
+	for len(values) > 0 {
+		var next []reflect.Value
+		for _, value := range values {
+			for i := 0; i < value.NumField(); i++ {
+				field := value.Type().Field(i)
+				if !field.Anonymous {
+					continue
+				}
+				fieldValue := value.Field(i)
+				ty := field.Type
+				if ty.Kind() == reflect.Ptr {
+					ty = ty.Elem()
+				}
+				if ty.Name() == typeName || ty.String() == typeName {
+					if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
+						return nil, fmt.Errorf("embedded %s in %s is nil", typeName, o.String())
+					}
+					return newFromValue(fieldValue), nil
+				}
+				if fieldValue.Kind() == reflect.Ptr {
+					if fieldValue.IsNil() || visited[fieldValue.Pointer()] {
+						continue
+					}
+					visited[fieldValue.Pointer()] = true
+					fieldValue = fieldValue.Elem()
+				}
+				if fieldValue.Kind() == reflect.Struct {
+					next = append(next, fieldValue)
+				}
+			}
+		}
+		values = next
+	}
+	return nil, fmt.Errorf("no embedded %s in %s", typeName, o.String())
+}
+
+// Implements checks if the object implements the interface type. Methods with pointer receivers count only if
+// the object is a pointer (or an addressable value, see NewCopy). Returns false if iface isn't an interface type.
+func (o *Obj) Implements(iface reflect.Type) bool {
+	if o.objType == nil || iface == nil || iface.Kind() != reflect.Interface {
+		return false
+	}
+	if o.objType.Implements(iface) {
+		return true
+	}
+	return o.objKind != reflect.Ptr && o.fieldsValue.CanAddr() && reflect.PtrTo(o.objType).Implements(iface)
+}
+
+// ImplementsAny checks if the object implements at least one of the interface types.
+func (o *Obj) ImplementsAny(ifaces []reflect.Type) bool {
+	for _, iface := range ifaces {
+		if o.Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// Embeds checks if the struct embeds the type at any depth (following embedded structs recursively).
+// A struct embedding *T embeds both *T and T.
+func (o *Obj) Embeds(t reflect.Type) bool {
+	if !o.IsStructOrPtrToStruct() || t == nil {
+		return false
+	}
+	return embeds(o.underlyingType, t, map[reflect.Type]bool{})
+}
+
+func embeds(ty, t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[ty] {
+		return false
+	}
+	visited[ty] = true
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType == t {
+			return true
+		}
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType == t || (fieldType.Kind() == reflect.Struct && embeds(fieldType, t, visited)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (o *Obj) getFields(listingType fieldListingType) []ObjField {
 	var fieldNames []string
 	switch listingType {
@@ -429,22 +790,220 @@ func (o *Obj) getFields(listingType fieldListingType) []ObjField {
 	return res
 }
 
+// FieldsWhereValue returns exported flattened fields with values for which pred returns true.
+func (o *Obj) FieldsWhereValue(pred func(value interface{}) bool) ([]ObjField, error) {
+	if !o.IsStructOrPtrToStruct() || !o.fieldsValue.IsValid() {
+		return nil, fmt.Errorf("cannot list field values of %s", o.String())
+	}
+	var res []ObjField
+	for _, field := range o.FieldsFlattened() {
+		if !field.IsExported() || !field.IsValid() {
+			continue
+		}
+		value, err := field.Get()
+		if err != nil {
+			return nil, err
+		}
+		if pred(value) {
+			res = append(res, field)
+		}
+	}
+	return res, nil
+}
+
+// FieldsWithTag returns exported flattened fields with a non-empty tag.
+func (o *Obj) FieldsWithTag(tagKey string) []ObjField {
+	return o.fieldsWhereTag(tagKey, func(value string) bool { return value != "" })
+}
+
+// FieldsWithTagValue returns exported flattened fields with the tag equal to the value.
+func (o *Obj) FieldsWithTagValue(tagKey, tagValue string) []ObjField {
+	return o.fieldsWhereTag(tagKey, func(value string) bool { return value == tagValue })
+}
+
+func (o *Obj) fieldsWhereTag(tagKey string, pred func(value string) bool) []ObjField {
+	var res []ObjField
+	for _, field := range o.FieldsFlattened() {
+		if field.IsValid() && field.IsExported() && pred(field.structField.Tag.Get(tagKey)) {
+			res = append(res, field)
+		}
+	}
+	return res
+}
+
+// FieldWithPath is a field with its dotted path (see Obj.FieldsFlattenedWithPath).
+type FieldWithPath struct {
+	// Path is the dotted path including embedded structs, for example "Address.Street".
+	Path  string
+	Field ObjField
+}
+
+// FieldsFlattenedWithPath returns the same fields as FieldsFlattened, each with its path through embedded structs.
+// Unlike names, paths are unique, so fields with the same name declared in different embedded structs can be
+// distinguished (and the returned fields are always the declared ones, not the shallowest with that name).
+//
+// Fields declared in nil embedded pointers are listed, but not valid.
+func (o *Obj) FieldsFlattenedWithPath() []FieldWithPath {
+	res := []FieldWithPath{}
+	if !o.IsStructOrPtrToStruct() {
+		return res
+	}
+	o.collectFieldsWithPath(o.underlyingType, "", nil, &res, map[reflect.Type]bool{})
+	return res
+}
+
+func (o *Obj) collectFieldsWithPath(ty reflect.Type, prefix string, index []int, res *[]FieldWithPath, visited map[reflect.Type]bool) {
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	if visited[ty] {
+		return
+	}
+	visited[ty] = true
+	defer delete(visited, ty)
+
+	for i := 0; i < ty.NumField(); i++ {
+		structField := ty.Field(i)
+		structField.Index = append(append([]int{}, index...), i)
+		path := joinPath(prefix, structField.Name)
+		if structField.Anonymous && isStructOrPtrToStruct(structField.Type) {
+			o.collectFieldsWithPath(structField.Type, path, structField.Index, res, visited)
+			continue
+		}
+		field := ObjField{
+			obj: o,
+			ObjFieldMetadata: ObjFieldMetadata{
+				name:        structField.Name,
+				structField: structField,
+				valid:       true,
+				fieldKind:   structField.Type.Kind(),
+				fieldType:   structField.Type,
+			},
+		}
+		if o.fieldsValue.IsValid() {
+			field.value, _ = o.fieldsValue.FieldByIndexErr(structField.Index)
+		}
+		*res = append(*res, FieldWithPath{Path: path, Field: field})
+	}
+}
+
+// FieldWithMeta is a field with metadata supplied from outside (see Obj.FieldMetadata).
+type FieldWithMeta struct {
+	Field ObjField
+	// Meta contains the metadata (for example "label", "placeholder", "help") for this field, never nil.
+	Meta map[string]string
+}
+
+// FieldMetadata returns flattened fields, each with its metadata from the registry (keyed by field name).
+// Since Go can't read comments at runtime, this can be used to add (for example) labels and help texts
+// to fields. Fields without an entry in the registry have empty metadata.
+func (o *Obj) FieldMetadata(registry map[string]map[string]string) []FieldWithMeta {
+	fields := o.FieldsFlattened()
+	res := make([]FieldWithMeta, len(fields))
+	for n, field := range fields {
+		meta := map[string]string{}
+		for k, v := range registry[field.Name()] {
+			meta[k] = v
+		}
+		res[n] = FieldWithMeta{Field: field, Meta: meta}
+	}
+	return res
+}
+
 // FindDoubleFields checks if this object has declared
 // multiple fields with a same name.
 // (by checking recursively Anonymous fields and their fields)
 func (o Obj) FindDoubleFields() []string {
-	fields := map[string]int{}
+	return append([]string{}, o.doubleFieldNames...)
+}
+
+// FindDoubleFieldsDetailed returns the same names as FindDoubleFields, each with the index sequences (see
+// ObjField.Index) of all its declarations, in FieldsAll order.
+func (o Obj) FindDoubleFieldsDetailed() map[string][][]int {
+	res := map[string][][]int{}
+	if len(o.doubleFieldNames) == 0 {
+		return res
+	}
+	indexes := map[string][][]int{}
+	collectFieldIndexes(o.objType, nil, indexes, map[reflect.Type]bool{})
+	for _, name := range o.doubleFieldNames {
+		res[name] = indexes[name]
+	}
+	return res
+}
+
+// collectFieldIndexes collects index sequences of all fields (like fieldsAll listing), by name.
+func collectFieldIndexes(ty reflect.Type, prefix []int, res map[string][][]int, visited map[reflect.Type]bool) {
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	if ty.Kind() != reflect.Struct || visited[ty] {
+		return
+	}
+	visited[ty] = true
+	defer delete(visited, ty)
+
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		index := append(append([]int{}, prefix...), i)
+		res[field.Name] = append(res[field.Name], index)
+		if field.Anonymous && isStructOrPtrToStruct(field.Type) {
+			collectFieldIndexes(field.Type, index, res, visited)
+		}
+	}
+}
+
+func findDoubleFieldNames(fieldNames []string) []string {
+	counters := map[string]int{}
 	res := []string{}
-	for _, f := range o.FieldsAll() {
-		counter := fields[f.name]
+	for _, name := range fieldNames {
+		counter := counters[name]
 		if counter == 1 {
-			res = append(res, f.name)
+			res = append(res, name)
 		}
-		fields[f.name] = counter + 1
+		counters[name] = counter + 1
+	}
+	return res
+}
+
+// Interface returns the wrapped value. For addressable (non pointer) objects (see NewCopy), this is the current
+// value, including changes made through the object. Returns nil for invalid objects.
+func (o *Obj) Interface() interface{} {
+	defer o.rlock()()
+	v := o.currentValue()
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// Elem returns an Obj wrapping the value the pointer points to. The returned object is addressable, so changes
+// made through it change the pointed-to value. For nil pointers an invalid object is returned, and for
+// non-pointers the object itself.
+func (o *Obj) Elem() *Obj {
+	if !o.IsPtr() {
+		return o
 	}
+	if !o.fieldsValue.IsValid() {
+		return New(nil)
+	}
+	res := newFromValue(o.fieldsValue)
+	res.mu = o.mu
 	return res
 }
 
+// IsNil checks if the value is nil: a nil pointer, map, slice, interface, channel or func (or no value at all).
+func (o *Obj) IsNil() bool {
+	v := reflect.ValueOf(o.iface)
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
 // IsPtr checks if the value is a pointer.
 func (o Obj) IsPtr() bool {
 	return o.objKind == reflect.Ptr
@@ -453,26 +1012,186 @@ func (o Obj) IsPtr() bool {
 // Field get a field wrapper.
 // Note that the field name can be invalid.
 // You can check the field validity using ObjField.IsValid().
+//
+// For nil pointers to struct, the field has type information (Type, Kind, Index), but is not valid.
+//
+// Like in Go, a promoted field name selects the shallowest declaration (for example Company.Number, and not
+// Company.Address.Number). A dotted path like "Address.Number" selects the field explicitly, see FieldByPath
+// (but unlike FieldByPath, nil pointers along the path are not allocated).
 func (o *Obj) Field(fieldName string) *ObjField {
-	if o.fieldsValue.IsValid() {
-		if metadata, found := o.fields[fieldName]; found {
-			return newObjField(o, metadata)
+	if metadata, found := o.fields[fieldName]; found {
+		return newObjField(o, metadata)
+	}
+	if strings.Contains(fieldName, ".") {
+		if field, err := o.fieldByPath(fieldName, false); err == nil {
+			return field
 		}
 	}
 	return newObjField(o, ObjFieldMetadata{name: fieldName, valid: false, fieldKind: reflect.Invalid})
 }
 
+// FieldCaseInsensitive returns a field by name ignoring case. An exact match has precedence, otherwise the
+// first match (in FieldsFlattened order) is returned. If there is no match, the field is invalid.
+func (o *Obj) FieldCaseInsensitive(name string) *ObjField {
+	if field := o.Field(name); field.IsValid() {
+		return field
+	}
+	for _, fieldName := range o.fieldNamesFlattenAnonymous {
+		if strings.EqualFold(fieldName, name) {
+			if field := o.Field(fieldName); field.IsValid() {
+				return field
+			}
+		}
+	}
+	return o.Field(name)
+}
+
+// FieldByIndex returns a field by its index sequence (see ObjField.Index). Unlike Field, no name lookup is
+// needed, so this is faster when the same field is needed many times. It can also return fields shadowed by
+// fields with the same name declared in an outer struct.
+//
+// If the index is invalid (or an embedded pointer along the way is nil), an invalid field is returned.
+func (o *Obj) FieldByIndex(index []int) *ObjField {
+	invalid := newObjField(o, ObjFieldMetadata{name: fmt.Sprint(index), valid: false, fieldKind: reflect.Invalid})
+	if !o.IsStructOrPtrToStruct() || !o.fieldsValue.IsValid() || len(index) == 0 {
+		return invalid
+	}
+
+	ty, value := o.underlyingType, o.fieldsValue
+	var structField reflect.StructField
+	for n, i := range index {
+		if n > 0 {
+			if ty.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					return invalid
+				}
+				ty, value = ty.Elem(), value.Elem()
+			}
+			if ty.Kind() != reflect.Struct {
+				return invalid
+			}
+		}
+		if i < 0 || i >= ty.NumField() {
+			return invalid
+		}
+		structField = ty.Field(i)
+		ty, value = structField.Type, value.Field(i)
+	}
+	structField.Index = append([]int(nil), index...)
+
+	return &ObjField{
+		obj:   o,
+		value: value,
+		ObjFieldMetadata: ObjFieldMetadata{
+			name:        structField.Name,
+			structField: structField,
+			valid:       true,
+			fieldKind:   structField.Type.Kind(),
+			fieldType:   structField.Type,
+		},
+	}
+}
+
+// FieldByPath returns a field by a dotted path, for example "Address.Street", "Tags.0" or "Owners.john.Name".
+// Path segments are struct field names, slice/array indexes or map keys, the same as in SetByPath and
+// BuildFromPaths. If the last segment is an index or a map key, the returned field is the element.
+//
+// If any segment doesn't exist (or is a nil pointer which can't be allocated), an invalid field is returned.
+// Nil pointers along the path are allocated when settable (i.e. when the root object is a pointer). Map values
+// are not addressable, so fields inside them can be read but not set.
+func (o *Obj) FieldByPath(path string) *ObjField {
+	field, err := o.fieldByPath(path, true)
+	if err != nil {
+		return newObjField(o, ObjFieldMetadata{name: path, valid: false, fieldKind: reflect.Invalid})
+	}
+	return field
+}
+
+// SetByPath sets a value by a dotted path (see FieldByPath), the object must be a pointer. Nil pointers, maps and
+// too short slices along the path are allocated, and the value is converted to the target type when possible
+// (see BuildFromPaths).
+func (o *Obj) SetByPath(path string, value interface{}) error {
+	if !o.IsPtr() {
+		return fmt.Errorf("cannot set %s in %s, pointer required", path, o.String())
+	}
+	if !o.fieldsValue.IsValid() {
+		return fmt.Errorf("cannot set %s in nil %s", path, o.String())
+	}
+	defer o.lock()()
+	if err := setPath(o.fieldsValue, path, value); err != nil {
+		return fmt.Errorf("path %s: %w", path, err)
+	}
+	return nil
+}
+
+func (o *Obj) fieldByPath(path string, allocate bool) (*ObjField, error) {
+	if !o.fieldsValue.IsValid() {
+		return nil, fmt.Errorf("invalid object %s", o.String())
+	}
+	if allocate {
+		defer o.lock()()
+	}
+	parent, value, err := resolvePath(o.fieldsValue, path, allocate)
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(path, ".")
+	segment := segments[len(segments)-1]
+	parentObj := newFromValue(parent)
+	parentObj.mu = o.mu
+	if parent.Kind() == reflect.Struct {
+		return parentObj.Field(segment), nil
+	}
+	return &ObjField{
+		obj:   parentObj,
+		value: value,
+		ObjFieldMetadata: ObjFieldMetadata{
+			name:      segment,
+			valid:     true,
+			fieldKind: value.Kind(),
+			fieldType: value.Type(),
+		},
+	}, nil
+}
+
 // Type returns the value type.
 // If kind is invalid, this will return a zero filled reflect.Type.
 func (o Obj) Type() reflect.Type {
 	return o.objType
 }
 
+// UnderlyingType returns the type the pointer points to, or the value type for non-pointers. For objects created
+// with NewFromType(ty) (which wraps a pointer to a new value), this is ty.
+func (o Obj) UnderlyingType() reflect.Type {
+	if o.objKind == reflect.Ptr {
+		return o.objType.Elem()
+	}
+	return o.objType
+}
+
 // Kind returns the value's kind.
 func (o Obj) Kind() reflect.Kind {
 	return o.objKind
 }
 
+// ElemType returns the element type of a slice, array, map, channel or pointer.
+// Doesn't need a value, so it works for objects created with NewFromType, too.
+func (o Obj) ElemType() (reflect.Type, error) {
+	switch o.objKind {
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Ptr, reflect.Slice:
+		return o.objType.Elem(), nil
+	}
+	return nil, fmt.Errorf("%s has no element type", o.String())
+}
+
+// KeyType returns the key type of a map.
+func (o Obj) KeyType() (reflect.Type, error) {
+	if o.objKind != reflect.Map {
+		return nil, fmt.Errorf("%s has no key type", o.String())
+	}
+	return o.objType.Key(), nil
+}
+
 func (o Obj) String() string {
 	if o.objType == nil {
 		return "nil"
@@ -498,6 +1217,98 @@ func (o *Obj) Methods() []ObjMethod {
 	return res
 }
 
+// PtrMethods returns methods with pointer receivers (see ObjMethod.RequiresPtr), in the same order as Methods.
+func (o *Obj) PtrMethods() []ObjMethod {
+	return o.methodsWhere(true)
+}
+
+// ValueMethods returns methods with value receivers, in the same order as Methods.
+func (o *Obj) ValueMethods() []ObjMethod {
+	return o.methodsWhere(false)
+}
+
+func (o *Obj) methodsWhere(requiresPtr bool) []ObjMethod {
+	res := []ObjMethod{}
+	for _, method := range o.Methods() {
+		if method.RequiresPtr() == requiresPtr {
+			res = append(res, method)
+		}
+	}
+	return res
+}
+
+// MethodsMatching returns methods (from the same method set as Methods) with names for which the predicate
+// returns true.
+func (o *Obj) MethodsMatching(predicate func(name string) bool) []ObjMethod {
+	res := []ObjMethod{}
+	for _, name := range o.methodNames {
+		if predicate(name) {
+			res = append(res, *o.Method(name))
+		}
+	}
+	return res
+}
+
+// MethodsWithPrefix returns methods with names starting with the prefix.
+func (o *Obj) MethodsWithPrefix(prefix string) []ObjMethod {
+	return o.MethodsMatching(func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// MethodNames returns method names, in the same order as Methods.
+func (o *Obj) MethodNames() []string {
+	return append([]string{}, o.methodNames...)
+}
+
+// FieldNames returns names of fields, in the same order as FieldsFlattened.
+func (o *Obj) FieldNames() []string {
+	return append([]string{}, o.fieldNamesFlattenAnonymous...)
+}
+
+// FieldNamesAll returns names of fields, in the same order as FieldsAll.
+func (o *Obj) FieldNamesAll() []string {
+	return append([]string{}, o.fieldNamesAll...)
+}
+
+// DispatchTable returns all valid methods as funcs (calling ObjMethod.Call), keyed by method name.
+func (o *Obj) DispatchTable() map[string]func(...interface{}) (*CallResult, error) {
+	res := map[string]func(...interface{}) (*CallResult, error){}
+	for _, method := range o.Methods() {
+		method := method
+		if method.IsValid() {
+			res[method.name] = method.Call
+		}
+	}
+	return res
+}
+
+// Signature contains the names of a method's input and output types (without the receiver).
+type Signature struct {
+	In  []string
+	Out []string
+}
+
+// MethodSignatures returns signatures of all methods, keyed by method name.
+func (o *Obj) MethodSignatures() map[string]Signature {
+	res := make(map[string]Signature, len(o.methodNames))
+	for _, name := range o.methodNames {
+		ty := methodSignature(o.methods[name].method)
+		signature := Signature{
+			In:  make([]string, ty.NumIn()),
+			Out: make([]string, ty.NumOut()),
+		}
+		for i := range signature.In {
+			signature.In[i] = ty.In(i).String()
+		}
+		for i := range signature.Out {
+			signature.Out[i] = ty.Out(i).String()
+		}
+		res[name] = signature
+	}
+	return res
+}
+
 // ObjField is a wrapper for the object's field.
 type ObjField struct {
 	obj   *Obj
@@ -512,14 +1323,21 @@ func newObjField(obj *Obj, metadata ObjFieldMetadata) *ObjField {
 		ObjFieldMetadata: metadata,
 	}
 
-	if metadata.valid && res.obj.IsStructOrPtrToStruct() {
-		res.value = obj.fieldsValue.FieldByName(res.name)
+	if metadata.valid && res.obj.IsStructOrPtrToStruct() && obj.fieldsValue.IsValid() {
+		// Invalid if declared in a nil embedded pointer:
+		res.value, _ = obj.fieldsValue.FieldByIndexErr(metadata.structField.Index)
 	}
 
 	return res
 }
 
 func (of *ObjField) assertValid() error {
+	if of.valid && !of.obj.fieldsValue.IsValid() {
+		return fmt.Errorf("field %s: nil pointer %s", of.name, of.obj.String())
+	}
+	if of.valid && !of.value.IsValid() {
+		return fmt.Errorf("field %s: nil embedded pointer in %s", of.name, of.obj.String())
+	}
 	if !of.IsValid() {
 		return fmt.Errorf("invalid field %s", of.name)
 	}
@@ -531,11 +1349,52 @@ func (of *ObjField) IsValid() bool {
 	return of.valid && of.value.IsValid()
 }
 
+// InvalidReason explains why the field is invalid, returns an empty string for valid fields.
+func (of *ObjField) InvalidReason() string {
+	if of.IsValid() {
+		return ""
+	}
+	switch {
+	case !of.obj.IsValid():
+		return "object is nil"
+	case !of.obj.IsStructOrPtrToStruct():
+		return fmt.Sprintf("not a struct (%s)", of.obj.String())
+	case !of.obj.fieldsValue.IsValid():
+		return fmt.Sprintf("nil pointer to struct (%s)", of.obj.String())
+	case of.valid:
+		return "declared in a nil embedded pointer"
+	}
+	if _, found := of.obj.fields[of.name]; found {
+		return "ambiguous (declared in multiple embedded structs)"
+	}
+	for _, name := range of.obj.fieldNamesAll {
+		if strings.EqualFold(name, of.name) {
+			return fmt.Sprintf("no such field (did you mean %s?)", name)
+		}
+	}
+	return "no such field"
+}
+
 // Name returns the field's name.
 func (of *ObjField) Name() string {
 	return of.name
 }
 
+// Index returns the field's index sequence, as used by reflect.Value.FieldByIndex and Obj.FieldByIndex.
+// Fields declared in embedded structs have multiple elements. Returns nil for invalid fields.
+func (of *ObjField) Index() []int {
+	if !of.valid {
+		return nil
+	}
+	return append([]int(nil), of.structField.Index...)
+}
+
+// StructField returns the field's reflect.StructField and false if no such field exists. For fields declared in
+// embedded structs, Index is the full index sequence (see Index).
+func (of *ObjField) StructField() (reflect.StructField, bool) {
+	return of.structField, of.valid
+}
+
 // Kind returns the field's kind.
 func (of *ObjField) Kind() reflect.Kind {
 	return of.fieldKind
@@ -546,6 +1405,21 @@ func (of *ObjField) Type() reflect.Type {
 	return of.fieldType
 }
 
+// DynamicType returns the type of the value currently stored in an interface field (nil if the interface is nil).
+// For non-interface fields, this is the same as Type().
+func (of *ObjField) DynamicType() (reflect.Type, error) {
+	if err := of.assertValid(); err != nil {
+		return nil, err
+	}
+	if of.fieldKind != reflect.Interface {
+		return of.fieldType, nil
+	}
+	if of.value.IsNil() {
+		return nil, nil
+	}
+	return of.value.Elem().Type(), nil
+}
+
 // Tag returns the value of this specific tag
 // or error if the field is invalid.
 func (of *ObjField) Tag(tag string) (string, error) {
@@ -582,6 +1456,51 @@ func (of *ObjField) TagExpanded(tag string) ([]string, error) {
 	return strings.Split(of.structField.Tag.Get(tag), ","), nil
 }
 
+// TagStructured parses a json-style tag, where the first comma separated element is the name and the rest are
+// options, for example `json:"name,omitempty"`. An empty name means the field name. If the tag is "-" (the field
+// should be skipped), the name is "-" and there are no options.
+func (of *ObjField) TagStructured(key string) (name string, options []string, err error) {
+	if err := of.assertValid(); err != nil {
+		return "", nil, err
+	}
+	tag := of.structField.Tag.Get(key)
+	if tag == "-" {
+		return "-", nil, nil
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = of.name
+	}
+	options = []string{}
+	for _, option := range parts[1:] {
+		if option = strings.TrimSpace(option); option != "" {
+			options = append(options, option)
+		}
+	}
+	return name, options, nil
+}
+
+// InTagSet checks if the field value (formatted as string) is one of the comma separated values
+// in the tag, for example `oneof:"a,b,c"`.
+func (of *ObjField) InTagSet(tagName string) (bool, error) {
+	allowed, err := of.TagExpanded(tagName)
+	if err != nil {
+		return false, err
+	}
+	value, err := of.Get()
+	if err != nil {
+		return false, err
+	}
+	str := fmt.Sprint(value)
+	for _, candidate := range allowed {
+		if strings.TrimSpace(candidate) == str {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // IsAnonymous checks if this is an anonymous (embedded) field.
 func (of *ObjField) IsAnonymous() bool {
 	if err := of.assertValid(); err != nil {
@@ -595,17 +1514,37 @@ func (of *ObjField) IsAnonymous() bool {
 }
 
 // IsExported returns true if the name starts with uppercase (i.e. field is public).
+// Unexported fields can't be set and can be read only in this package.
 func (of *ObjField) IsExported() bool {
 	return of.structField.PkgPath == ""
 }
 
+// PkgPath returns the package path qualifying the name of an unexported field, empty for exported fields.
+func (of *ObjField) PkgPath() string {
+	return of.structField.PkgPath
+}
+
+// IsTime checks if the field type is time.Time or *time.Time.
+func (of *ObjField) IsTime() bool {
+	if of.fieldType == nil {
+		return false
+	}
+	return of.fieldType == timeType || (of.fieldKind == reflect.Ptr && of.fieldType.Elem() == timeType)
+}
+
 // IsSettable checks if this field is settable.
 func (of *ObjField) IsSettable() bool {
 	return of.value.CanSet()
 }
 
 // Set sets a value for this field or error if field is invalid (or not settable).
+//
+// The value must be assignable to the field type, for interface fields this means any value
+// implementing the interface. A nil value sets nil pointers, interfaces, maps, slices, channels and funcs.
 func (of *ObjField) Set(value interface{}) error {
+	if of.valid && !of.obj.fieldsValue.IsValid() {
+		return fmt.Errorf("cannot set field %s: nil pointer %s, allocate the value first (see EnsureAllocated)", of.name, of.obj.String())
+	}
 	if err := of.assertValid(); err != nil {
 		return err
 	}
@@ -614,7 +1553,13 @@ func (of *ObjField) Set(value interface{}) error {
 		return fmt.Errorf("field %s in %T not settable", of.name, of.obj.iface)
 	}
 
-	of.value.Set(reflect.ValueOf(value))
+	v, err := assignableValue(value, of.fieldType)
+	if err != nil {
+		return fmt.Errorf("field %s in %T: %w", of.name, of.obj.iface, err)
+	}
+
+	defer of.obj.lock()()
+	of.value.Set(v)
 
 	return nil
 }
@@ -628,9 +1573,37 @@ func (of *ObjField) Get() (interface{}, error) {
 		return nil, fmt.Errorf("cannot read unexported field %T.%s", of.obj.iface, of.name)
 	}
 
+	defer of.obj.rlock()()
 	return of.value.Interface(), nil
 }
 
+// Addr returns a pointer to the field (for example *int for an int field), so that it can be passed to functions
+// setting values through pointers (like sql.Rows.Scan). The field must be exported and addressable, i.e. the root
+// object is a pointer.
+func (of *ObjField) Addr() (interface{}, error) {
+	if err := of.assertValid(); err != nil {
+		return nil, err
+	}
+	if !of.IsSettable() {
+		return nil, fmt.Errorf("field %s in %T not addressable", of.name, of.obj.iface)
+	}
+	return of.value.Addr().Interface(), nil
+}
+
+// AsObj returns an Obj wrapping the field value.
+//
+// If the field is addressable (the root object is a pointer), changes made through the returned Obj
+// change the field itself.
+func (of *ObjField) AsObj() (*Obj, error) {
+	if err := of.assertValid(); err != nil {
+		return nil, err
+	}
+	if !of.IsExported() {
+		return nil, fmt.Errorf("cannot read unexported field %T.%s", of.obj.iface, of.name)
+	}
+	return newFromValue(of.value), nil
+}
+
 // ObjMethod is a wrapper for an object method.
 // The name of the method can be invalid.
 type ObjMethod struct {
@@ -650,13 +1623,76 @@ func (om *ObjMethod) Name() string {
 	return om.name
 }
 
+// InvalidReason explains why the method is invalid, returns an empty string for valid methods.
+func (om *ObjMethod) InvalidReason() string {
+	if om.IsValid() {
+		return ""
+	}
+	if !om.obj.IsValid() {
+		return "object is nil"
+	}
+	ty := om.obj.objType
+	if ty.Kind() != reflect.Ptr {
+		if _, found := reflect.PtrTo(ty).MethodByName(om.name); found {
+			return "requires pointer receiver but object is not a pointer"
+		}
+	}
+	if promotedFrom := embeddedWithMethod(om.obj.underlyingType, om.name); len(promotedFrom) > 1 {
+		return fmt.Sprintf("ambiguous promotion (from %s)", strings.Join(promotedFrom, ", "))
+	}
+	for _, name := range om.obj.methodNames {
+		if strings.EqualFold(name, om.name) {
+			return fmt.Sprintf("no such method (did you mean %s?)", name)
+		}
+	}
+	return "no such method"
+}
+
+// embeddedWithMethod returns names of the struct's embedded fields with the method (with any receiver).
+func embeddedWithMethod(ty reflect.Type, name string) []string {
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil
+	}
+	var res []string
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() != reflect.Ptr && fieldType.Kind() != reflect.Interface {
+			fieldType = reflect.PtrTo(fieldType)
+		}
+		if _, found := fieldType.MethodByName(name); found {
+			res = append(res, field.Name)
+		}
+	}
+	return res
+}
+
 const (
 	onlyInTypes  = 0
 	onlyOutTypes = 1
 )
 
+// receiver returns the value on which the method is called. For addressable (non pointer) objects this is the
+// address of the value if the method has a pointer receiver.
+func (om *ObjMethod) receiver() reflect.Value {
+	if om.obj.objKind != reflect.Ptr && om.obj.fieldsValue.CanAddr() {
+		if om.valid && om.method.Type.In(0).Kind() == reflect.Ptr {
+			return om.obj.fieldsValue.Addr()
+		}
+		return om.obj.fieldsValue
+	}
+	return reflect.ValueOf(om.obj.iface)
+}
+
 func (om *ObjMethod) methodTypes(kind int) []reflect.Type {
-	m := reflect.ValueOf(om.obj.iface).MethodByName(om.name)
+	recv := om.receiver()
+	if !recv.IsValid() {
+		return []reflect.Type{}
+	}
+	m := recv.MethodByName(om.name)
 	if !m.IsValid() {
 		return []reflect.Type{}
 	}
@@ -674,10 +1710,14 @@ func (om *ObjMethod) methodTypes(kind int) []reflect.Type {
 	for i := 0; i < tyNum; i++ {
 		out[i] = tyFn(i)
 	}
+	if kind == onlyInTypes && ty.IsVariadic() {
+		out[tyNum-1] = out[tyNum-1].Elem()
+	}
 	return out
 }
 
 // InTypes returns an slice with this method's input types.
+// For variadic methods, the last type is the type of the variadic arguments (string for ...string).
 func (om *ObjMethod) InTypes() []reflect.Type {
 	return om.methodTypes(onlyInTypes)
 }
@@ -687,26 +1727,129 @@ func (om *ObjMethod) OutTypes() []reflect.Type {
 	return om.methodTypes(onlyOutTypes)
 }
 
+// NumIn returns the number of input parameters (without the receiver), 0 for invalid methods.
+func (om *ObjMethod) NumIn() int {
+	if !om.IsValid() {
+		return 0
+	}
+	return om.method.Type.NumIn() - 1
+}
+
+// NumOut returns the number of output parameters, 0 for invalid methods.
+func (om *ObjMethod) NumOut() int {
+	if !om.IsValid() {
+		return 0
+	}
+	return om.method.Type.NumOut()
+}
+
+// IsVariadic checks if the method's last input parameter is variadic, false for invalid methods.
+func (om *ObjMethod) IsVariadic() bool {
+	return om.IsValid() && om.method.Type.IsVariadic()
+}
+
 // IsValid returns this method's validity.
 func (om *ObjMethod) IsValid() bool {
 	return om.valid
 }
 
+// RequiresPtr returns true if the method is declared only on the pointer type (i.e. it has a pointer receiver), so
+// it can't be called on a (non addressable) value. Works also for methods which aren't valid because the
+// object is not a pointer.
+func (om *ObjMethod) RequiresPtr() bool {
+	ty := om.obj.objType
+	if ty == nil {
+		return false
+	}
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	if _, found := ty.MethodByName(om.name); found {
+		return false
+	}
+	_, found := reflect.PtrTo(ty).MethodByName(om.name)
+	return found
+}
+
+// Func returns the method bound to the object as a func value, for example:
+//
+//	fn, err := obj.Method("Hi").Func()
+//	hi := fn.(func(string) string)
+func (om *ObjMethod) Func() (interface{}, error) {
+	if !om.obj.IsValid() {
+		return nil, fmt.Errorf("invalid object type %T for method %s", om.obj.iface, om.name)
+	}
+	if !om.IsValid() {
+		return nil, fmt.Errorf("invalid method %s in %T", om.name, om.obj.iface)
+	}
+	return om.receiver().MethodByName(om.name).Interface(), nil
+}
+
 // Call calls this method.
 // Note that in the error returning value is not the error from the method call.
+//
+// Variadic arguments are passed flat (Call("a", "b") for ...string), but a single slice for the variadic
+// arguments is expanded, too (Call([]string{"a", "b"})).
+//
+// Registered call hooks (see RegisterCallHook) are invoked after the call.
 func (om *ObjMethod) Call(args ...interface{}) (*CallResult, error) {
+	res, err := om.call(args, false)
+	runCallHooks(om.name, args, res, err)
+	return res, err
+}
+
+// MustCall is like Call, but panics if the call fails (invalid method or arguments, or a panic in the method).
+// The panic value is an error with the method name, wrapping the original error. Errors returned by the method
+// itself don't panic, they are in the result.
+//
+// Meant for tests and code paths where failures are programming errors, otherwise use Call.
+func (om *ObjMethod) MustCall(args ...interface{}) *CallResult {
+	res, err := om.Call(args...)
+	if err == nil && res.PanicInfo != nil {
+		err = res.Error
+	}
+	if err != nil {
+		panic(fmt.Errorf("call %s: %w", om.name, err))
+	}
+	return res
+}
+
+// CallSlice calls a variadic method with the variadic arguments in a slice, like reflect.Value.CallSlice:
+// the last argument is the slice for the variadic parameter. For example, for Format(sep string, parts ...string)
+// use CallSlice([]interface{}{",", []string{"a", "b"}}). Unlike Call, the arguments are never expanded, so
+// this works also when the variadic arguments themselves are slices (for example ...[]string).
+//
+// Calling a method which isn't variadic is an error.
+func (om *ObjMethod) CallSlice(args []interface{}) (*CallResult, error) {
+	res, err := om.call(args, true)
+	runCallHooks(om.name, args, res, err)
+	return res, err
+}
+
+func (om *ObjMethod) call(args []interface{}, slice bool) (*CallResult, error) {
 	if !om.obj.IsValid() {
 		return nil, fmt.Errorf("invalid object type %T for method %s", om.obj.iface, om.name)
 	}
 	if !om.IsValid() {
 		return nil, fmt.Errorf("invalid method %s in %T", om.name, om.obj.iface)
 	}
-	in := make([]reflect.Value, len(args)+1)
-	in[0] = reflect.ValueOf(om.obj.iface)
-	for n := range args {
-		in[n+1] = reflect.ValueOf(args[n])
+	var in []reflect.Value
+	var err error
+	if slice {
+		in, err = om.callSliceArgs(args)
+	} else {
+		in, err = om.callArgs(args)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out, panicInfo := callRecovering(om.method.Func, in, slice)
+	if panicInfo != nil {
+		return &CallResult{
+			Error:     fmt.Errorf("method %s panicked: %v", om.name, panicInfo.Value),
+			PanicInfo: panicInfo,
+		}, nil
 	}
-	out := om.method.Func.Call(in)
 	res := make([]interface{}, len(out))
 	for n := range out {
 		res[n] = out[n].Interface()
@@ -714,10 +1857,125 @@ func (om *ObjMethod) Call(args ...interface{}) (*CallResult, error) {
 	return newCallResult(res), nil
 }
 
+// callArgs checks the arguments against the method's input types and prepares the values (including the
+// receiver) for the call. Nil arguments are converted to zero values of the parameter type.
+func (om *ObjMethod) callArgs(args []interface{}) ([]reflect.Value, error) {
+	ty := om.method.Type
+	fixed := ty.NumIn() - 1
+	if ty.IsVariadic() {
+		fixed--
+	}
+	if len(args) < fixed || (!ty.IsVariadic() && len(args) > fixed) {
+		return nil, fmt.Errorf("method %s expects %d arguments, got %d", om.name, fixed, len(args))
+	}
+
+	if ty.IsVariadic() && len(args) == fixed+1 && args[fixed] != nil && reflect.TypeOf(args[fixed]).AssignableTo(ty.In(ty.NumIn()-1)) {
+		// A single slice for the variadic arguments is expanded:
+		variadic := reflect.ValueOf(args[fixed])
+		expanded := make([]interface{}, fixed, fixed+variadic.Len())
+		copy(expanded, args[:fixed])
+		for i := 0; i < variadic.Len(); i++ {
+			expanded = append(expanded, variadic.Index(i).Interface())
+		}
+		args = expanded
+	}
+
+	in := make([]reflect.Value, len(args)+1)
+	in[0] = om.receiver()
+	for n := range args {
+		var paramType reflect.Type
+		if n < fixed {
+			paramType = ty.In(1 + n)
+		} else {
+			paramType = ty.In(ty.NumIn() - 1).Elem()
+		}
+		v, err := assignableValue(args[n], paramType)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d of method %s: %w", n, om.name, err)
+		}
+		in[n+1] = v
+	}
+	return in, nil
+}
+
+// callSliceArgs prepares arguments like callArgs, but for CallSlice (the last argument is the variadic slice).
+func (om *ObjMethod) callSliceArgs(args []interface{}) ([]reflect.Value, error) {
+	ty := om.method.Type
+	if !ty.IsVariadic() {
+		return nil, fmt.Errorf("method %s is not variadic", om.name)
+	}
+	if len(args) != ty.NumIn()-1 {
+		return nil, fmt.Errorf("method %s expects %d arguments, got %d", om.name, ty.NumIn()-1, len(args))
+	}
+	in := make([]reflect.Value, len(args)+1)
+	in[0] = om.receiver()
+	for n := range args {
+		v, err := assignableValue(args[n], ty.In(1+n))
+		if err != nil {
+			return nil, fmt.Errorf("argument %d of method %s: %w", n, om.name, err)
+		}
+		in[n+1] = v
+	}
+	return in, nil
+}
+
+// callRecovering calls the function and recovers from a panic (if any).
+func callRecovering(fn reflect.Value, in []reflect.Value, slice bool) (out []reflect.Value, panicInfo *PanicInfo) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 64*1024)
+			panicInfo = &PanicInfo{Value: r, Stack: buf[:runtime.Stack(buf, false)]}
+		}
+	}()
+	if slice {
+		return fn.CallSlice(in), nil
+	}
+	return fn.Call(in), nil
+}
+
+// CallPartial calls the method like Call, but missing trailing arguments are zero values of their types.
+// For variadic methods, only the missing fixed arguments are added.
+func (om *ObjMethod) CallPartial(args ...interface{}) (*CallResult, error) {
+	if om.IsValid() {
+		fixed := om.method.Type.NumIn() - 1
+		if om.method.Type.IsVariadic() {
+			fixed--
+		}
+		for n := len(args); n < fixed; n++ {
+			args = append(args, reflect.Zero(om.method.Type.In(1+n)).Interface())
+		}
+	}
+	return om.Call(args...)
+}
+
+// CallRetry calls the method up to attempts times, sleeping backoff between calls, until the call
+// result is not an error (see CallResult.IsError). The last result is returned.
+//
+// Errors returned by this function (invalid method, invalid arguments) are never retried.
+func (om *ObjMethod) CallRetry(attempts int, backoff time.Duration, args ...interface{}) (*CallResult, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := om.Call(args...)
+		if err != nil || !res.IsError() || attempt >= attempts {
+			return res, err
+		}
+		time.Sleep(backoff)
+	}
+}
+
 // CallResult is a wrapper of a method call result.
 type CallResult struct {
 	Result []interface{}
 	Error  error
+	// PanicInfo is non-nil if the method panicked. In that case Result is empty and Error describes the panic.
+	PanicInfo *PanicInfo
+}
+
+// PanicInfo describes a panic recovered in a method call.
+type PanicInfo struct {
+	// Value is the value passed to panic().
+	Value interface{}
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
 }
 
 func newCallResult(res []interface{}) *CallResult {
@@ -725,8 +1983,10 @@ func newCallResult(res []interface{}) *CallResult {
 	if len(res) == 0 {
 		return cr
 	}
+	// The last value can be of any type implementing error (not only the error interface),
+	// but a nil pointer of such type is not an error:
 	errorCandidate := res[len(res)-1]
-	if errorCandidate != nil {
+	if !isNil(errorCandidate) {
 		if err, is := errorCandidate.(error); is {
 			cr.Error = err
 		}
@@ -734,7 +1994,58 @@ func newCallResult(res []interface{}) *CallResult {
 	return cr
 }
 
+// Len returns the number of returned values.
+func (cr *CallResult) Len() int {
+	return len(cr.Result)
+}
+
+// Get returns the i-th returned value, or an error if there is no such value.
+func (cr *CallResult) Get(i int) (interface{}, error) {
+	if i < 0 || i >= len(cr.Result) {
+		return nil, fmt.Errorf("no result %d, number of results: %d", i, len(cr.Result))
+	}
+	return cr.Result[i], nil
+}
+
+// String returns the i-th returned value if it is a string (or a type with string as the underlying type).
+func (cr *CallResult) String(i int) (string, error) {
+	value, err := cr.Get(i)
+	if err != nil {
+		return "", err
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.String {
+		return "", fmt.Errorf("result %d is %T, not a string", i, value)
+	}
+	return v.String(), nil
+}
+
+// Int returns the i-th returned value if it is an integer (of any int or uint type which fits into int64).
+func (cr *CallResult) Int(i int) (int64, error) {
+	value, err := cr.Get(i)
+	if err != nil {
+		return 0, err
+	}
+	v := reflect.ValueOf(value)
+	switch {
+	case isIntKind(v.Kind()):
+		return v.Int(), nil
+	case isUintKind(v.Kind()):
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("result %d (%d) overflows int64", i, v.Uint())
+		}
+		return int64(v.Uint()), nil
+	}
+	return 0, fmt.Errorf("result %d is %T, not an integer", i, value)
+}
+
 // IsError checks if the last value is a non-nil error.
 func (cr *CallResult) IsError() bool {
 	return cr.Error != nil
 }
+
+// Err returns the error returned by the method (the last value, if it is a non-nil error), or nil if the
+// method returned no error (or doesn't return errors at all). Same as the Error field.
+func (cr *CallResult) Err() error {
+	return cr.Error
+}
@@ -1,11 +1,17 @@
 package reflector
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"reflect"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/tkrajina/go-reflector/reflector/tmp"
@@ -78,6 +84,28 @@ func TestString(t *testing.T) {
 	assert.Equal(t, New(&Person{}).Field("bu").ObjFieldMetadata, New(&Person{}).Field("bu").ObjFieldMetadata)
 }
 
+func TestAcquireReleaseObj(t *testing.T) {
+	t.Parallel()
+	for i := 0; i < 10; i++ {
+		p := Person{}
+		obj := AcquireObj(&p)
+		assert.Equal(t, "*reflector.Person", obj.String())
+		assert.Nil(t, obj.Field("Number").Set(i))
+		assert.Equal(t, i, p.Number)
+		ReleaseObj(obj)
+
+		obj = AcquireObj(CustomType(i)).WithMutex()
+		assert.Equal(t, "reflector.CustomType", obj.String())
+		assert.Equal(t, 1, len(obj.Methods()))
+		ReleaseObj(obj)
+	}
+
+	obj := AcquireObj(nil)
+	assert.False(t, obj.IsValid())
+	assert.Nil(t, obj.mu)
+	ReleaseObj(obj)
+}
+
 func TestNilStringPtr(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "*string", New((*string)(nil)).String())
@@ -89,14 +117,171 @@ func TestNilStringPtr(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestFieldAddr(t *testing.T) {
+	t.Parallel()
+	var b Building
+	ptr, err := New(&b).Field("Floors").Addr()
+	assert.Nil(t, err)
+	*ptr.(*int) = 7
+	assert.Equal(t, 7, b.Floors)
+
+	ptr, err = New(&b).Field("Address").Addr()
+	assert.Nil(t, err)
+	*ptr.(**Address) = &Address{Street: "Main"}
+	assert.Equal(t, "Main", b.Address.Street)
+
+	_, err = New(b).Field("Floors").Addr()
+	assert.NotNil(t, err)
+	assert.Equal(t, "field Floors in reflector.Building not addressable", err.Error())
+	_, err = New(&b).Field("Invalid").Addr()
+	assert.NotNil(t, err)
+	_, err = New(&tmp.TestStruct{}).Field("unexported").Addr()
+	assert.NotNil(t, err)
+}
+
+func TestEnsureAllocated(t *testing.T) {
+	t.Parallel()
+	var b Building
+	address, err := New(&b).Field("Address").AsObj()
+	assert.Nil(t, err)
+	assert.True(t, address.IsNil())
+	assert.Nil(t, address.EnsureAllocated())
+	assert.False(t, address.IsNil())
+	assert.NotNil(t, b.Address)
+	assert.Nil(t, address.Field("Street").Set("Main"))
+	assert.Equal(t, &Address{Street: "Main"}, b.Address)
+
+	// Already allocated:
+	allocated := b.Address
+	assert.Nil(t, address.EnsureAllocated())
+	assert.Same(t, allocated, b.Address)
+
+	// Bind into a nil out pointer:
+	var out struct{ Person *Person }
+	person, err := New(&out).Field("Person").AsObj()
+	assert.Nil(t, err)
+	assert.Nil(t, person.EnsureAllocated())
+	assert.Nil(t, person.FromNestedMap(map[string]interface{}{"Name": "John"}))
+	assert.Equal(t, "John", out.Person.Name)
+
+	err = New((*Person)(nil)).EnsureAllocated()
+	assert.NotNil(t, err)
+	assert.Equal(t, "cannot allocate *reflector.Person, not settable", err.Error())
+	// Not addressable:
+	address, err = New(Building{}).Field("Address").AsObj()
+	assert.Nil(t, err)
+	assert.NotNil(t, address.EnsureAllocated())
+	assert.NotNil(t, New(Person{}).EnsureAllocated())
+	assert.NotNil(t, New(nil).EnsureAllocated())
+}
+
+func TestInterfaceAndElem(t *testing.T) {
+	t.Parallel()
+	p := &Person{Name: "John"}
+	obj := New(p)
+	assert.Nil(t, obj.Field("Name").Set("Jane"))
+	assert.Same(t, p, obj.Interface())
+	assert.Equal(t, Person{Name: "Jane"}, obj.Elem().Interface())
+
+	// The pointee is addressable:
+	assert.Nil(t, obj.Elem().Field("Name").Set("Jack"))
+	assert.Equal(t, "Jack", p.Name)
+	assert.True(t, obj.Elem().Method("Subtract").IsValid())
+
+	// Addressable copy returns the current value:
+	c := NewCopy(Person{})
+	assert.Nil(t, c.Field("Name").Set("John"))
+	assert.Equal(t, Person{Name: "John"}, c.Interface())
+	assert.Same(t, c, c.Elem())
+
+	assert.Equal(t, 5, New(5).Interface())
+	assert.Nil(t, New(nil).Interface())
+	assert.False(t, New((*Person)(nil)).Elem().IsValid())
+}
+
+func TestIsNil(t *testing.T) {
+	t.Parallel()
+	var m map[string]int
+	var s []int
+	var i interface{}
+	for _, value := range []interface{}{nil, (*Person)(nil), m, s, i, (func())(nil), (chan int)(nil)} {
+		assert.True(t, New(value).IsNil(), "%T", value)
+	}
+	for _, value := range []interface{}{&Person{}, Person{}, map[string]int{}, []int{}, 0, ""} {
+		assert.False(t, New(value).IsNil(), "%T", value)
+	}
+}
+
+type WithAddressPtr struct {
+	Name string
+	*Address
+}
+
+type RecursiveNode struct {
+	*RecursiveNode
+	Value int
+}
+
+func TestEmbeddedPtrFields(t *testing.T) {
+	t.Parallel()
+	{
+		obj := New(&WithAddressPtr{})
+		assert.Equal(t, []string{"Name", "Street", "Number"}, obj.FieldNames())
+		assert.Equal(t, []string{"Name", "Address", "Street", "Number"}, obj.FieldNamesAll())
+
+		// Nil embedded pointer, metadata is available:
+		street := obj.Field("Street")
+		assert.False(t, street.IsValid())
+		assert.Equal(t, reflect.TypeOf(""), street.Type())
+		assert.Equal(t, "declared in a nil embedded pointer", street.InvalidReason())
+		_, err := street.Get()
+		assert.NotNil(t, err)
+		assert.Equal(t, "field Street: nil embedded pointer in *reflector.WithAddressPtr", err.Error())
+		assert.NotNil(t, street.Set("Main"))
+	}
+	{
+		w := WithAddressPtr{Address: &Address{Street: "Main"}}
+		obj := New(&w)
+		value, err := obj.Field("Street").Get()
+		assert.Nil(t, err)
+		assert.Equal(t, "Main", value)
+		assert.Nil(t, obj.Field("Number").Set(7))
+		assert.Equal(t, 7, w.Number)
+		assert.Equal(t, 3, len(obj.FieldsFlattened()))
+		assert.Equal(t, 4, len(obj.FieldsAll()))
+	}
+	{
+		// Recursive embedding:
+		obj := New(&RecursiveNode{RecursiveNode: &RecursiveNode{Value: 1}, Value: 2})
+		assert.Equal(t, []string{"Value"}, obj.FieldNames())
+		assert.Equal(t, []string{"RecursiveNode", "Value"}, obj.FieldNamesAll())
+		value, err := obj.Field("Value").Get()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, value)
+	}
+}
+
 func TestNilStructPtr(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "*reflector.Person", New((*Person)(nil)).String())
 	assert.Equal(t, 2, len(New((*Person)(nil)).Fields()))
 	assert.Equal(t, 4, len(New((*Person)(nil)).Methods()))
+	assert.True(t, New((*Person)(nil)).IsNil())
+
+	// Type information is available:
+	for _, field := range New((*Person)(nil)).Fields() {
+		assert.NotNil(t, field.Type())
+		assert.False(t, field.IsValid())
+	}
+	assert.Equal(t, reflect.Int, New((*Person)(nil)).Field("Number").Kind())
 
 	err := New((*Person)(nil)).Field("Number").Set(17)
 	assert.NotNil(t, err)
+	assert.Equal(t, "cannot set field Number: nil pointer *reflector.Person, allocate the value first (see EnsureAllocated)", err.Error())
+
+	_, err = New((*Person)(nil)).Field("Number").Get()
+	assert.NotNil(t, err)
+	assert.Equal(t, "field Number: nil pointer *reflector.Person", err.Error())
 
 	v, err := New((*Person)(nil)).Field("Bu").Get()
 	assert.Nil(t, v)
@@ -180,6 +365,273 @@ func TestListFieldsAllWithDoubleFields(t *testing.T) {
 	assert.Equal(t, fields[3].Name(), "Number")
 }
 
+func TestFieldsWhereValue(t *testing.T) {
+	t.Parallel()
+	obj := New(&Person{Name: "John", Address: Address{Number: -1}})
+
+	fields, err := obj.FieldsWhereValue(func(value interface{}) bool { return value == "" })
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fields))
+	assert.Equal(t, "Street", fields[0].Name())
+
+	fields, err = obj.FieldsWhereValue(func(value interface{}) bool {
+		i, is := value.(int)
+		return is && i < 0
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fields))
+	assert.Equal(t, "Number", fields[0].Name())
+
+	// Unexported fields are skipped:
+	fields, err = New(tmp.TestStruct{}).FieldsWhereValue(func(value interface{}) bool { return true })
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fields))
+	assert.Equal(t, "Exported", fields[0].Name())
+
+	_, err = New(1).FieldsWhereValue(func(value interface{}) bool { return true })
+	assert.NotNil(t, err)
+	_, err = New((*Person)(nil)).FieldsWhereValue(func(value interface{}) bool { return true })
+	assert.NotNil(t, err)
+}
+
+type MixedCase struct {
+	URL  string
+	Url  string
+	Name string
+	name string
+}
+
+func TestFieldCaseInsensitive(t *testing.T) {
+	t.Parallel()
+	obj := New(&Person{})
+	assert.Equal(t, "Street", obj.FieldCaseInsensitive("street").Name())
+	assert.Equal(t, "Street", obj.FieldCaseInsensitive("STREET").Name())
+	assert.Equal(t, "Name", obj.FieldCaseInsensitive("Name").Name())
+	assert.False(t, obj.FieldCaseInsensitive("nothing").IsValid())
+	assert.Equal(t, "nothing", obj.FieldCaseInsensitive("nothing").Name())
+
+	// Exact match has precedence, then the first one:
+	mixed := New(&MixedCase{})
+	assert.Equal(t, "Url", mixed.FieldCaseInsensitive("Url").Name())
+	assert.Equal(t, "URL", mixed.FieldCaseInsensitive("url").Name())
+	assert.Equal(t, "name", mixed.FieldCaseInsensitive("name").Name())
+	assert.Equal(t, "Name", mixed.FieldCaseInsensitive("NAME").Name())
+
+	assert.False(t, New(1).FieldCaseInsensitive("a").IsValid())
+	_ = MixedCase{}.name
+}
+
+func TestFieldsWithTag(t *testing.T) {
+	t.Parallel()
+	fieldNames := func(fields []ObjField) []string {
+		res := []string{}
+		for _, field := range fields {
+			res = append(res, field.Name())
+		}
+		return res
+	}
+	obj := New(&Person{})
+	assert.Equal(t, []string{"Name", "Street", "Number"}, fieldNames(obj.FieldsWithTag("tag")))
+	assert.Equal(t, []string{"Street"}, fieldNames(obj.FieldsWithTag("tag2")))
+	assert.Equal(t, []string{}, fieldNames(obj.FieldsWithTag("nothing")))
+	assert.Equal(t, []string{"Street"}, fieldNames(obj.FieldsWithTagValue("tag", "be")))
+	assert.Equal(t, []string{}, fieldNames(obj.FieldsWithTagValue("tag", "b")))
+
+	// Unexported fields are skipped:
+	assert.Equal(t, []string{}, fieldNames(New(tmp.TestStruct{}).FieldsWithTag("aaa")))
+	assert.Equal(t, []string{}, fieldNames(New(1).FieldsWithTag("tag")))
+}
+
+func TestStructField(t *testing.T) {
+	t.Parallel()
+	obj := New(&Person{})
+
+	sf, found := obj.Field("Name").StructField()
+	assert.True(t, found)
+	assert.Equal(t, "Name", sf.Name)
+	assert.Equal(t, "bu", sf.Tag.Get("tag"))
+	assert.Equal(t, []int{0}, sf.Index)
+	assert.False(t, sf.Anonymous)
+
+	sf, found = obj.Field("Address").StructField()
+	assert.True(t, found)
+	assert.True(t, sf.Anonymous)
+
+	sf, found = obj.Field("Number").StructField()
+	assert.True(t, found)
+	assert.Equal(t, []int{1, 1}, sf.Index)
+	assert.Equal(t, reflect.TypeOf(Address{}).Field(1).Offset, sf.Offset)
+
+	_, found = obj.Field("Invalid").StructField()
+	assert.False(t, found)
+	// Type information for nil pointers:
+	_, found = New((*Person)(nil)).Field("Name").StructField()
+	assert.True(t, found)
+}
+
+func TestFieldsFlattenedWithPath(t *testing.T) {
+	t.Parallel()
+	var a Ambiguous
+	obj := New(&a)
+	fields := obj.FieldsFlattenedWithPath()
+	paths := []string{}
+	for n, field := range fields {
+		paths = append(paths, field.Path)
+		assert.Equal(t, obj.FieldsFlattened()[n].Name(), field.Field.Name())
+		assert.True(t, field.Field.IsValid())
+	}
+	assert.Equal(t, []string{"Address.Street", "Address.Number", "Company.Address.Street", "Company.Address.Number", "Company.Number"}, paths)
+
+	assert.Nil(t, fields[2].Field.Set("Main"))
+	assert.Nil(t, fields[3].Field.Set(3))
+	assert.Equal(t, Ambiguous{Company: Company{Address: Address{Street: "Main", Number: 3}}}, a)
+	value, err := fields[2].Field.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "Main", value)
+
+	// Nil embedded pointer:
+	fields = New(&WithAddressPtr{}).FieldsFlattenedWithPath()
+	assert.Equal(t, 3, len(fields))
+	assert.Equal(t, "Address.Street", fields[1].Path)
+	assert.False(t, fields[1].Field.IsValid())
+	_, err = fields[1].Field.Get()
+	assert.NotNil(t, err)
+
+	assert.Empty(t, New(1).FieldsFlattenedWithPath())
+	assert.Equal(t, 3, len(New((*Person)(nil)).FieldsFlattenedWithPath()))
+}
+
+func TestFieldMetadata(t *testing.T) {
+	t.Parallel()
+	registry := map[string]map[string]string{
+		"Name":   {"label": "Full name", "placeholder": "John Smith"},
+		"Street": {"help": "Street name"},
+	}
+	fields := New(&Person{}).FieldMetadata(registry)
+	assert.Equal(t, 3, len(fields))
+	assert.Equal(t, "Name", fields[0].Field.Name())
+	assert.Equal(t, map[string]string{"label": "Full name", "placeholder": "John Smith"}, fields[0].Meta)
+	assert.Equal(t, "Street", fields[1].Field.Name())
+	assert.Equal(t, map[string]string{"help": "Street name"}, fields[1].Meta)
+	assert.Equal(t, "Number", fields[2].Field.Name())
+	assert.Equal(t, map[string]string{}, fields[2].Meta)
+
+	// The registry is not modified through the result:
+	fields[0].Meta["label"] = "changed"
+	assert.Equal(t, "Full name", registry["Name"]["label"])
+
+	assert.Empty(t, New(1).FieldMetadata(registry))
+}
+
+func TestEmbeddedTypes(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []reflect.Type{reflect.TypeOf(Address{})}, New(&Person{}).EmbeddedTypes())
+	assert.Equal(t, []reflect.Type{reflect.TypeOf(Address{}), reflect.TypeOf(Company{})}, New(Ambiguous{}).EmbeddedTypes())
+	assert.Equal(t, []reflect.Type{reflect.TypeOf(Greeter1{}), reflect.TypeOf(&Greeter2{})}, New(AmbiguousGreeter{}).EmbeddedTypes())
+	assert.Empty(t, New(Address{}).EmbeddedTypes())
+	assert.Empty(t, New(1).EmbeddedTypes())
+	assert.Empty(t, New(nil).EmbeddedTypes())
+}
+
+type Recursive struct {
+	*Recursive
+	Company
+}
+
+func TestEmbeds(t *testing.T) {
+	t.Parallel()
+	assert.True(t, New(&Person{}).Embeds(reflect.TypeOf(Address{})))
+	assert.False(t, New(&Person{}).Embeds(reflect.TypeOf(Company{})))
+	assert.False(t, New(&Person{}).Embeds(reflect.TypeOf("")))
+
+	// Deeper:
+	assert.True(t, New(Ambiguous{}).Embeds(reflect.TypeOf(Company{})))
+	assert.True(t, New(Recursive{}).Embeds(reflect.TypeOf(Address{})))
+	assert.True(t, New(Recursive{}).Embeds(reflect.TypeOf(Recursive{})))
+	assert.True(t, New(Recursive{}).Embeds(reflect.TypeOf(&Recursive{})))
+	assert.False(t, New(Recursive{}).Embeds(reflect.TypeOf(Person{})))
+
+	// Pointers:
+	assert.True(t, New(AmbiguousGreeter{}).Embeds(reflect.TypeOf(Greeter2{})))
+	assert.True(t, New(AmbiguousGreeter{}).Embeds(reflect.TypeOf(&Greeter2{})))
+	assert.False(t, New(AmbiguousGreeter{}).Embeds(reflect.TypeOf(&Greeter1{})))
+
+	assert.False(t, New(1).Embeds(reflect.TypeOf(Address{})))
+	assert.False(t, New(&Person{}).Embeds(nil))
+}
+
+func TestEmbeddedObj(t *testing.T) {
+	t.Parallel()
+	p := Person{Name: "John", Address: Address{Street: "Main"}}
+	address, err := New(&p).EmbeddedObj("Address")
+	assert.Nil(t, err)
+	assert.Equal(t, reflect.TypeOf(Address{}), address.Type())
+	assert.Nil(t, address.Field("Street").Set("Other"))
+	assert.Equal(t, "Other", p.Street)
+	// Only fields of the embedded type:
+	assert.False(t, address.Field("Name").IsValid())
+
+	address, err = New(p).EmbeddedObj("reflector.Address")
+	assert.Nil(t, err)
+	assert.NotNil(t, address.Field("Street").Set("Other"))
+
+	// Deeper:
+	a := Ambiguous{Company: Company{Number: 7}}
+	company, err := New(&a).EmbeddedObj("Company")
+	assert.Nil(t, err)
+	number, err := company.Field("Number").Get()
+	assert.Nil(t, err)
+	assert.Equal(t, 7, number)
+	r := Recursive{}
+	r.Recursive = &r
+	_, err = New(&r).EmbeddedObj("Address")
+	assert.Nil(t, err)
+	_, err = New(&r).EmbeddedObj("Nothing")
+	assert.NotNil(t, err)
+
+	// Nil embedded pointer:
+	_, err = New(&AmbiguousGreeter{}).EmbeddedObj("Greeter2")
+	assert.NotNil(t, err)
+	g2, err := New(&AmbiguousGreeter{Greeter2: &Greeter2{}}).EmbeddedObj("Greeter2")
+	assert.Nil(t, err)
+	assert.True(t, g2.Method("Greet").IsValid())
+
+	_, err = New(&p).EmbeddedObj("Company")
+	assert.NotNil(t, err)
+	_, err = New(1).EmbeddedObj("Address")
+	assert.NotNil(t, err)
+}
+
+type subtracter interface {
+	Subtract(a, b int) int
+}
+
+type adder interface {
+	Add(a, b, c int) int
+}
+
+func TestImplements(t *testing.T) {
+	t.Parallel()
+	subtracterType := reflect.TypeOf((*subtracter)(nil)).Elem()
+	adderType := reflect.TypeOf((*adder)(nil)).Elem()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+
+	assert.True(t, New(Person{}).Implements(adderType))
+	assert.False(t, New(Person{}).Implements(subtracterType))
+	assert.True(t, New(&Person{}).Implements(adderType))
+	assert.True(t, New(&Person{}).Implements(subtracterType))
+	assert.True(t, NewCopy(Person{}).Implements(subtracterType))
+
+	assert.False(t, New(Person{}).Implements(errorType))
+	assert.False(t, New(Person{}).Implements(reflect.TypeOf(Person{})))
+	assert.False(t, New(Person{}).Implements(nil))
+	assert.False(t, New(nil).Implements(errorType))
+
+	assert.True(t, New(Person{}).ImplementsAny([]reflect.Type{errorType, adderType}))
+	assert.False(t, New(Person{}).ImplementsAny([]reflect.Type{errorType, subtracterType}))
+	assert.False(t, New(Person{}).ImplementsAny(nil))
+}
+
 func TestFindDoubleFields(t *testing.T) {
 	t.Parallel()
 	obj := New(Company{})
@@ -189,6 +641,93 @@ func TestFindDoubleFields(t *testing.T) {
 	assert.Equal(t, fields[0], "Number")
 }
 
+func TestFindDoubleFieldsDetailed(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, map[string][][]int{"Number": {{0, 1}, {1}}}, New(Company{}).FindDoubleFieldsDetailed())
+	assert.Equal(t, map[string][][]int{
+		"Street":  {{0, 0}, {1, 0, 0}},
+		"Number":  {{0, 1}, {1, 0, 1}, {1, 1}},
+		"Address": {{0}, {1, 0}},
+	}, New(&Ambiguous{}).FindDoubleFieldsDetailed())
+
+	for _, obj := range []*Obj{New(Company{}), New(&Ambiguous{})} {
+		detailed := obj.FindDoubleFieldsDetailed()
+		assert.Equal(t, len(obj.FindDoubleFields()), len(detailed))
+		for _, name := range obj.FindDoubleFields() {
+			for _, index := range detailed[name] {
+				assert.Equal(t, name, obj.FieldByIndex(index).Name())
+			}
+		}
+	}
+	assert.Empty(t, New(&Person{}).FindDoubleFieldsDetailed())
+	assert.Empty(t, New(nil).FindDoubleFieldsDetailed())
+}
+
+type Ambiguous struct {
+	Address
+	Company
+}
+
+func TestFieldInvalidReason(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "", New(&Person{}).Field("Name").InvalidReason())
+	assert.Equal(t, "", New(Company{}).Field("Number").InvalidReason())
+	assert.Equal(t, "no such field", New(&Person{}).Field("Nothing").InvalidReason())
+	assert.Equal(t, "no such field (did you mean Name?)", New(&Person{}).Field("name").InvalidReason())
+	assert.Equal(t, "ambiguous (declared in multiple embedded structs)", New(Ambiguous{}).Field("Number").InvalidReason())
+	assert.Equal(t, "", New(Ambiguous{}).Field("Street").InvalidReason())
+	assert.Equal(t, "object is nil", New(nil).Field("Name").InvalidReason())
+	assert.Equal(t, "not a struct (int)", New(1).Field("Name").InvalidReason())
+	assert.Equal(t, "nil pointer to struct (*reflector.Person)", New((*Person)(nil)).Field("Name").InvalidReason())
+}
+
+func TestFieldIndex(t *testing.T) {
+	t.Parallel()
+	c := Company{Address: Address{Street: "Main", Number: 1}, Number: 2}
+	obj := New(&c)
+
+	assert.Equal(t, []int{1}, obj.Field("Number").Index())
+	assert.Equal(t, []int{0, 0}, obj.Field("Street").Index())
+	assert.Equal(t, []int{0}, obj.Field("Address").Index())
+	assert.Nil(t, obj.Field("Invalid").Index())
+
+	for _, field := range obj.FieldsFlattened() {
+		byIndex := obj.FieldByIndex(field.Index())
+		assert.True(t, byIndex.IsValid())
+		assert.Equal(t, field.Name(), byIndex.Name())
+		assert.Equal(t, field.Index(), byIndex.Index())
+		v1, err := field.Get()
+		assert.Nil(t, err)
+		v2, err := byIndex.Get()
+		assert.Nil(t, err)
+		assert.Equal(t, v1, v2)
+		assert.Equal(t, reflect.ValueOf(c).FieldByIndex(field.Index()).Interface(), v2)
+	}
+
+	// The shadowed Address.Number:
+	shadowed := obj.FieldByIndex([]int{0, 1})
+	assert.Equal(t, "Number", shadowed.Name())
+	assert.Nil(t, shadowed.Set(17))
+	assert.Equal(t, 17, c.Address.Number)
+	assert.Equal(t, 2, c.Number)
+	value, err := shadowed.TagExpanded("tag")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bi"}, value)
+
+	for _, index := range [][]int{nil, {}, {2}, {-1}, {1, 0}, {0, 5}, {0, 0, 0}} {
+		assert.False(t, obj.FieldByIndex(index).IsValid(), fmt.Sprint(index))
+	}
+	assert.False(t, New(1).FieldByIndex([]int{0}).IsValid())
+	assert.False(t, New((*Company)(nil)).FieldByIndex([]int{0}).IsValid())
+
+	// Nil embedded pointer:
+	r := Recursive{}
+	assert.False(t, New(&r).FieldByIndex([]int{0, 1}).IsValid())
+	assert.True(t, New(&r).FieldByIndex([]int{0}).IsValid())
+	r.Recursive = &Recursive{}
+	assert.Equal(t, "Company", New(&r).FieldByIndex([]int{0, 1}).Name())
+}
+
 func TestListFieldsOnPointer(t *testing.T) {
 	t.Parallel()
 	p := &Person{}
@@ -294,120 +833,677 @@ func TestSetField(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "ulica", value)
 
-	assert.Equal(t, "ulica", p.Street)
+	assert.Equal(t, "ulica", p.Street)
+}
+
+func TestWithMutex(t *testing.T) {
+	t.Parallel()
+	p := Person{}
+	obj := New(&p).WithMutex()
+	assert.Equal(t, obj, obj.WithMutex())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(t, obj.Field("Number").Set(i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := obj.Field("Number").Get()
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCustomTypeMethods(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, len(New(CustomType(1)).Methods()), 1)
+	ct := CustomType(1)
+	assert.Equal(t, len(New(&ct).Methods()), 2)
+}
+
+func TestMethods(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, len(New(Person{}).Methods()), 3)
+	assert.Equal(t, len(New(&Person{}).Methods()), 4)
+
+	// Check that when we twice get a field, the metadata field is cached only once:
+	assert.Equal(t, New(Person{}).Method("Add").ObjMethodMetadata, New(Person{}).Method("Add").ObjMethodMetadata)
+	assert.Equal(t, New(&Person{}).Method("Add").ObjMethodMetadata, New(&Person{}).Method("Add").ObjMethodMetadata)
+}
+
+func TestDispatchTable(t *testing.T) {
+	t.Parallel()
+	table := New(&Person{Name: "Jane"}).DispatchTable()
+	assert.Equal(t, 4, len(table))
+
+	res, err := table["Hi"]("John")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Hi John my name is Jane"}, res.Result)
+
+	res, err = table["Subtract"](5, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{3}, res.Result)
+
+	assert.Equal(t, 3, len(New(Person{}).DispatchTable()))
+	assert.Equal(t, 0, len(New(nil).DispatchTable()))
+}
+
+func TestMethodSignatures(t *testing.T) {
+	t.Parallel()
+	signatures := New(&Person{}).MethodSignatures()
+	assert.Equal(t, 4, len(signatures))
+	assert.Equal(t, Signature{In: []string{"int", "int", "int"}, Out: []string{"int"}}, signatures["Add"])
+	assert.Equal(t, Signature{In: []string{"int", "int"}, Out: []string{"int"}}, signatures["Subtract"])
+	assert.Equal(t, Signature{In: []string{"bool"}, Out: []string{"string", "*int", "error"}}, signatures["ReturnsError"])
+	assert.Equal(t, Signature{In: []string{"string"}, Out: []string{"string"}}, signatures["Hi"])
+
+	assert.Equal(t, 3, len(New(Person{}).MethodSignatures()))
+	assert.Equal(t, 0, len(New(nil).MethodSignatures()))
+}
+
+func TestCallMethod(t *testing.T) {
+	t.Parallel()
+	obj := New(&Person{})
+	method := obj.Method("Add")
+	res, err := method.Call(2, 3, 6)
+	assert.Nil(t, err)
+	assert.False(t, res.IsError())
+	assert.Equal(t, len(res.Result), 1)
+	assert.Equal(t, res.Result[0], 11)
+
+	assert.True(t, method.IsValid())
+	assert.Equal(t, len(method.InTypes()), 3)
+	assert.Equal(t, len(method.OutTypes()), 1)
+
+	sub, err := obj.Method("Subtract").Call(5, 6)
+	assert.Nil(t, err)
+	assert.Equal(t, sub.Result, []interface{}{-1})
+}
+
+func TestMethodFunc(t *testing.T) {
+	t.Parallel()
+	p := &Person{Name: "Jane"}
+	fn, err := New(p).Method("Hi").Func()
+	assert.Nil(t, err)
+	hi, is := fn.(func(string) string)
+	assert.True(t, is)
+	assert.Equal(t, "Hi John my name is Jane", hi("John"))
+
+	fn, err = New(p).Method("Subtract").Func()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, fn.(func(int, int) int)(5, 2))
+
+	_, err = New(Person{}).Method("Subtract").Func()
+	assert.NotNil(t, err)
+	_, err = New(nil).Method("Hi").Func()
+	assert.NotNil(t, err)
+}
+
+func TestCallInvalidMethod(t *testing.T) {
+	t.Parallel()
+	obj := New(&Person{})
+	method := obj.Method("AddAdddd")
+	res, err := method.Call([]interface{}{2, 3, 6})
+	assert.NotNil(t, err)
+	assert.Nil(t, res)
+
+	assert.Equal(t, len(method.InTypes()), 0)
+	assert.Equal(t, len(method.OutTypes()), 0)
+}
+
+func TestMethodsValidityOnPtr(t *testing.T) {
+	t.Parallel()
+	ct := CustomType(1)
+	obj := New(&ct)
+
+	assert.True(t, obj.IsPtr())
+
+	assert.True(t, obj.Method("Method1").IsValid())
+	assert.True(t, obj.Method("Method2").IsValid())
+
+	{
+		res, err := obj.Method("Method1").Call()
+		assert.Nil(t, err)
+		assert.Equal(t, res.Result, []interface{}{"yep"})
+	}
+	{
+		res, err := obj.Method("Method2").Call()
+		assert.Nil(t, err)
+		assert.Equal(t, res.Result, []interface{}{7})
+	}
+}
+
+func TestMethodsValidityOnNonPtr(t *testing.T) {
+	t.Parallel()
+	obj := New(CustomType(1))
+
+	assert.False(t, obj.IsPtr())
+
+	assert.True(t, obj.Method("Method1").IsValid())
+	// False because it's not a pointer
+	assert.False(t, obj.Method("Method2").IsValid())
+
+	{
+		res, err := obj.Method("Method1").Call()
+		assert.Nil(t, err)
+		assert.Equal(t, res.Result, []interface{}{"yep"})
+	}
+	{
+		_, err := obj.Method("Method2").Call()
+		assert.NotNil(t, err)
+	}
+}
+
+func TestRequiresPtr(t *testing.T) {
+	t.Parallel()
+	obj := New(CustomType(1))
+	assert.False(t, obj.Method("Method1").RequiresPtr())
+	// Invalid, but known to require a pointer:
+	assert.True(t, obj.Method("Method2").RequiresPtr())
+	assert.False(t, obj.Method("Invalid").RequiresPtr())
+	assert.False(t, New(nil).Method("Method1").RequiresPtr())
+
+	ct := CustomType(1)
+	ptr := New(&ct)
+	assert.False(t, ptr.Method("Method1").RequiresPtr())
+	assert.True(t, ptr.Method("Method2").RequiresPtr())
+	assert.Equal(t, []ObjMethod{*ptr.Method("Method2")}, ptr.PtrMethods())
+	assert.Equal(t, []ObjMethod{*ptr.Method("Method1")}, ptr.ValueMethods())
+
+	assert.Empty(t, obj.PtrMethods())
+	assert.Equal(t, 1, len(obj.ValueMethods()))
+	assert.Equal(t, 1, len(NewCopy(ct).PtrMethods()))
+}
+
+func TestMethodsOnAddressableCopy(t *testing.T) {
+	t.Parallel()
+	ct := CustomType(1)
+	obj := NewCopy(ct)
+
+	assert.False(t, obj.IsPtr())
+	assert.Equal(t, 2, len(obj.Methods()))
+	assert.True(t, obj.Method("Method1").IsValid())
+	assert.True(t, obj.Method("Method2").IsValid())
+	assert.Equal(t, 0, len(obj.Method("Method2").InTypes()))
+	assert.Equal(t, 1, len(obj.Method("Method2").OutTypes()))
+
+	res, err := obj.Method("Method2").Call()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{7}, res.Result)
+
+	fn, err := obj.Method("Method2").Func()
+	assert.Nil(t, err)
+	assert.Equal(t, 7, fn.(func() int)())
+
+	// But New() with a value still can't call pointer receiver methods:
+	assert.False(t, New(ct).Method("Method2").IsValid())
+}
+
+func TestNewCopy(t *testing.T) {
+	t.Parallel()
+	p := Person{Name: "Jane"}
+	obj := NewCopy(p)
+
+	assert.True(t, obj.Field("Name").IsSettable())
+	assert.Nil(t, obj.Field("Name").Set("Mary"))
+	name, err := obj.Field("Name").Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "Mary", name)
+	// The original is unchanged:
+	assert.Equal(t, "Jane", p.Name)
+
+	// Value receiver methods see the changed copy:
+	res, err := obj.Method("Hi").Call("John")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Hi John my name is Mary"}, res.Result)
+
+	res, err = obj.Method("Subtract").Call(3, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{2}, res.Result)
+
+	assert.False(t, NewCopy(nil).IsValid())
+}
+
+func testCallMethod(t *testing.T, callValue bool, lenResult int) bool {
+	obj := New(&Person{})
+	res, err := obj.Method("ReturnsError").Call(callValue)
+	assert.Nil(t, err)
+	assert.Equal(t, len(res.Result), lenResult)
+	return res.IsError()
+}
+
+func TestCallMethodWithoutErrResult(t *testing.T) {
+	t.Parallel()
+	isErr := testCallMethod(t, true, 3)
+	assert.True(t, isErr)
+}
+
+func TestCallMethodWithErrResult(t *testing.T) {
+	t.Parallel()
+	isErr := testCallMethod(t, false, 3)
+	assert.False(t, isErr)
+}
+
+type Panicky struct{}
+
+func (Panicky) Divide(a, b int) int { return a / b }
+
+func TestCallPanic(t *testing.T) {
+	t.Parallel()
+	obj := New(Panicky{})
+
+	res, err := obj.Method("Divide").Call(6, 3)
+	assert.Nil(t, err)
+	assert.Nil(t, res.PanicInfo)
+	assert.Equal(t, []interface{}{2}, res.Result)
+
+	res, err = obj.Method("Divide").Call(6, 0)
+	assert.Nil(t, err)
+	assert.True(t, res.IsError())
+	assert.Empty(t, res.Result)
+	assert.NotNil(t, res.PanicInfo)
+	assert.Contains(t, fmt.Sprint(res.PanicInfo.Value), "divide by zero")
+	assert.Contains(t, string(res.PanicInfo.Stack), "Divide")
+	assert.Contains(t, res.Error.Error(), "method Divide panicked")
+}
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+	obj := New(&Person{})
+	names := func(fields []ObjField) []string {
+		res := []string{}
+		for _, field := range fields {
+			res = append(res, field.Name())
+		}
+		return res
+	}
+	assert.Equal(t, names(obj.FieldsFlattened()), obj.FieldNames())
+	assert.Equal(t, names(obj.FieldsAll()), obj.FieldNamesAll())
+	assert.Equal(t, []string{"Name", "Street", "Number"}, obj.FieldNames())
+	assert.Equal(t, []string{"Name", "Address", "Street", "Number"}, obj.FieldNamesAll())
+
+	methodNames := []string{}
+	for _, method := range obj.Methods() {
+		methodNames = append(methodNames, method.Name())
+	}
+	assert.Equal(t, methodNames, obj.MethodNames())
+
+	// Returned slices are copies:
+	obj.FieldNames()[0] = "changed"
+	assert.Equal(t, "Name", obj.FieldNames()[0])
+
+	assert.Empty(t, New(nil).FieldNames())
+	assert.Empty(t, New(nil).MethodNames())
+}
+
+func TestMethodsMatching(t *testing.T) {
+	t.Parallel()
+	methodNames := func(methods []ObjMethod) []string {
+		res := []string{}
+		for _, method := range methods {
+			res = append(res, method.Name())
+		}
+		return res
+	}
+	obj := New(&Person{})
+	assert.Equal(t, []string{"Hi"}, methodNames(obj.MethodsWithPrefix("H")))
+	assert.Equal(t, []string{"Add", "Subtract"}, methodNames(obj.MethodsMatching(func(name string) bool {
+		return strings.HasSuffix(name, "d") || strings.HasSuffix(name, "t")
+	})))
+	assert.Empty(t, obj.MethodsWithPrefix("Handle"))
+	assert.Equal(t, obj.Methods(), obj.MethodsWithPrefix(""))
+
+	// Pointer receiver methods only for pointers (or addressable values):
+	assert.Empty(t, New(Person{}).MethodsWithPrefix("Sub"))
+	assert.Equal(t, []string{"Subtract"}, methodNames(NewCopy(Person{}).MethodsWithPrefix("Sub")))
+}
+
+func TestMustCall(t *testing.T) {
+	t.Parallel()
+	obj := New(Panicky{})
+	assert.Equal(t, []interface{}{2}, obj.Method("Divide").MustCall(6, 3).Result)
+
+	for _, call := range []func(){
+		func() { obj.Method("Divide").MustCall(6, 0) },
+		func() { obj.Method("Divide").MustCall(6) },
+		func() { obj.Method("Invalid").MustCall() },
+	} {
+		func() {
+			defer func() {
+				err, is := recover().(error)
+				assert.True(t, is)
+				assert.Contains(t, err.Error(), "call ")
+				assert.NotNil(t, errors.Unwrap(err))
+			}()
+			call()
+			t.Fatal("should panic")
+		}()
+	}
+
+	// Returned errors don't panic:
+	res := New(Person{}).Method("ReturnsError").MustCall(true)
+	assert.True(t, res.IsError())
+}
+
+func TestCallInvalidArgs(t *testing.T) {
+	t.Parallel()
+	obj := New(Panicky{})
+
+	_, err := obj.Method("Divide").Call(6)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "expects 2 arguments, got 1")
+
+	_, err = obj.Method("Divide").Call(6, "0")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "argument 1 of method Divide: cannot use string as int")
+
+	_, err = obj.Method("Divide").Call(6, nil)
+	assert.NotNil(t, err)
+
+	res, err := New(&Person{}).Method("ReturnsError").Call(true)
+	assert.Nil(t, err)
+	assert.True(t, res.IsError())
+}
+
+type Greeter1 struct{}
+
+func (Greeter1) Greet() string { return "1" }
+
+type Greeter2 struct{}
+
+func (*Greeter2) Greet() string { return "2" }
+
+type AmbiguousGreeter struct {
+	Greeter1
+	*Greeter2
+}
+
+func TestMethodInvalidReason(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "", New(CustomType(1)).Method("Method1").InvalidReason())
+	assert.Equal(t, "requires pointer receiver but object is not a pointer", New(CustomType(1)).Method("Method2").InvalidReason())
+	assert.Equal(t, "", New(new(CustomType)).Method("Method2").InvalidReason())
+	assert.Equal(t, "", NewCopy(CustomType(1)).Method("Method2").InvalidReason())
+	assert.Equal(t, "no such method", New(CustomType(1)).Method("Method3").InvalidReason())
+	assert.Equal(t, "no such method (did you mean Method1?)", New(CustomType(1)).Method("method1").InvalidReason())
+	assert.Equal(t, "ambiguous promotion (from Greeter1, Greeter2)", New(&AmbiguousGreeter{}).Method("Greet").InvalidReason())
+	assert.Equal(t, "object is nil", New(nil).Method("Method1").InvalidReason())
+}
+
+func TestCallResultError(t *testing.T) {
+	t.Parallel()
+	obj := New(&Person{})
+
+	res, err := obj.Method("Add").Call(1, 2, 3)
+	assert.Nil(t, err)
+	assert.Nil(t, res.Err())
+
+	res, err = obj.Method("ReturnsError").Call(false)
+	assert.Nil(t, err)
+	assert.Nil(t, res.Err())
+
+	res, err = obj.Method("ReturnsError").Call(true)
+	assert.Nil(t, err)
+	assert.NotNil(t, res.Err())
+	assert.Equal(t, res.Result[2], res.Err())
+	assert.Equal(t, res.Error, res.Err())
+}
+
+func TestCallResultAccessors(t *testing.T) {
+	t.Parallel()
+	res, err := New(Person{}).Method("ReturnsError").Call(false)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, res.Len())
+
+	value, err := res.Get(0)
+	assert.Nil(t, err)
+	assert.Equal(t, "jen", value)
+	_, err = res.Get(3)
+	assert.NotNil(t, err)
+	_, err = res.Get(-1)
+	assert.NotNil(t, err)
+
+	str, err := res.String(0)
+	assert.Nil(t, err)
+	assert.Equal(t, "jen", str)
+	_, err = res.String(1)
+	assert.NotNil(t, err)
+	_, err = res.Int(0)
+	assert.NotNil(t, err)
+	_, err = res.Int(2)
+	assert.NotNil(t, err)
+
+	// Pointer receiver:
+	res, err = New(&Person{}).Method("Subtract").Call(5, 7)
+	assert.Nil(t, err)
+	n, err := res.Int(0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-2), n)
+	_, err = res.Int(1)
+	assert.NotNil(t, err)
+
+	res = &CallResult{Result: []interface{}{UpperString("aaa"), uint8(7), uint64(math.MaxUint64)}}
+	str, err = res.String(0)
+	assert.Nil(t, err)
+	assert.Equal(t, "aaa", str)
+	n, err = res.Int(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), n)
+	_, err = res.Int(2)
+	assert.NotNil(t, err)
+}
+
+func TestCallPartial(t *testing.T) {
+	t.Parallel()
+	obj := New(&Person{Name: "John"})
+
+	res, err := obj.Method("Add").CallPartial(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1}, res.Result)
+
+	res, err = obj.Method("Add").CallPartial()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{0}, res.Result)
+
+	res, err = obj.Method("Add").CallPartial(1, 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{6}, res.Result)
+
+	res, err = obj.Method("Hi").CallPartial()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Hi  my name is John"}, res.Result)
+
+	_, err = obj.Method("Add").CallPartial(1, 2, 3, 4)
+	assert.NotNil(t, err)
+	_, err = obj.Method("Invalid").CallPartial()
+	assert.NotNil(t, err)
+}
+
+type Formatter struct {
+	Name string
 }
 
-func TestCustomTypeMethods(t *testing.T) {
-	t.Parallel()
-	assert.Equal(t, len(New(CustomType(1)).Methods()), 1)
-	ct := CustomType(1)
-	assert.Equal(t, len(New(&ct).Methods()), 2)
+func (f Formatter) Format(sep string, parts ...string) string {
+	return f.Name + ":" + strings.Join(parts, sep)
 }
 
-func TestMethods(t *testing.T) {
+func TestCallVariadic(t *testing.T) {
 	t.Parallel()
-	assert.Equal(t, len(New(Person{}).Methods()), 3)
-	assert.Equal(t, len(New(&Person{}).Methods()), 4)
+	method := New(Formatter{Name: "John"}).Method("Format")
+	assert.Equal(t, []reflect.Type{reflect.TypeOf(""), reflect.TypeOf("")}, method.InTypes())
+
+	for _, data := range []struct {
+		args     []interface{}
+		expected string
+	}{
+		{args: []interface{}{","}, expected: "John:"},
+		{args: []interface{}{",", "a"}, expected: "John:a"},
+		{args: []interface{}{",", "a", "b", "c"}, expected: "John:a,b,c"},
+		{args: []interface{}{",", []string{"a", "b"}}, expected: "John:a,b"},
+		{args: []interface{}{",", []string{}}, expected: "John:"},
+	} {
+		res, err := method.Call(data.args...)
+		assert.Nil(t, err, fmt.Sprint(data.args))
+		assert.Equal(t, []interface{}{data.expected}, res.Result, fmt.Sprint(data.args))
+	}
 
-	// Check that when we twice get a field, the metadata field is cached only once:
-	assert.Equal(t, New(Person{}).Method("Add").ObjMethodMetadata, New(Person{}).Method("Add").ObjMethodMetadata)
-	assert.Equal(t, New(&Person{}).Method("Add").ObjMethodMetadata, New(&Person{}).Method("Add").ObjMethodMetadata)
+	_, err := method.Call()
+	assert.NotNil(t, err)
+	_, err = method.Call(",", 1)
+	assert.NotNil(t, err)
+	_, err = method.Call(",", []int{1})
+	assert.NotNil(t, err)
+	_, err = method.Call(",", nil)
+	assert.NotNil(t, err)
 }
 
-func TestCallMethod(t *testing.T) {
+func (f Formatter) Join(parts ...[]string) string {
+	res := make([]string, len(parts))
+	for n := range parts {
+		res[n] = strings.Join(parts[n], "+")
+	}
+	return strings.Join(res, ",")
+}
+
+func TestCallSlice(t *testing.T) {
 	t.Parallel()
-	obj := New(&Person{})
-	method := obj.Method("Add")
-	res, err := method.Call(2, 3, 6)
+	obj := New(Formatter{Name: "John"})
+
+	res, err := obj.Method("Format").CallSlice([]interface{}{",", []string{"a", "b"}})
 	assert.Nil(t, err)
-	assert.False(t, res.IsError())
-	assert.Equal(t, len(res.Result), 1)
-	assert.Equal(t, res.Result[0], 11)
+	assert.Equal(t, []interface{}{"John:a,b"}, res.Result)
 
-	assert.True(t, method.IsValid())
-	assert.Equal(t, len(method.InTypes()), 3)
-	assert.Equal(t, len(method.OutTypes()), 1)
+	res, err = obj.Method("Format").CallSlice([]interface{}{",", nil})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"John:"}, res.Result)
 
-	sub, err := obj.Method("Substract").Call(5, 6)
+	// The slice is not expanded:
+	res, err = obj.Method("Join").CallSlice([]interface{}{[][]string{{"a", "b"}, {"c"}}})
 	assert.Nil(t, err)
-	assert.Equal(t, sub.Result, []interface{}{-1})
+	assert.Equal(t, []interface{}{"a+b,c"}, res.Result)
+
+	_, err = obj.Method("Format").CallSlice([]interface{}{",", "a", "b"})
+	assert.NotNil(t, err)
+	_, err = obj.Method("Format").CallSlice([]interface{}{",", "a"})
+	assert.NotNil(t, err)
+	_, err = New(&Person{}).Method("Add").CallSlice([]interface{}{1, 2, 3})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not variadic")
+	_, err = obj.Method("Invalid").CallSlice(nil)
+	assert.NotNil(t, err)
 }
 
-func TestCallInvalidMethod(t *testing.T) {
+func TestMethodNumInOut(t *testing.T) {
 	t.Parallel()
-	obj := New(&Person{})
-	method := obj.Method("AddAdddd")
-	res, err := method.Call([]interface{}{2, 3, 6})
-	assert.NotNil(t, err)
-	assert.Nil(t, res)
+	for _, data := range []struct {
+		method   *ObjMethod
+		in, out  int
+		variadic bool
+	}{
+		{method: New(&Person{}).Method("Add"), in: 3, out: 1},
+		{method: New(&Person{}).Method("Subtract"), in: 2, out: 1},
+		{method: New(&Person{}).Method("ReturnsError"), in: 1, out: 3},
+		{method: NewCopy(CustomType(1)).Method("Method2"), in: 0, out: 1},
+		{method: New(Formatter{}).Method("Format"), in: 2, out: 1, variadic: true},
+		{method: New(CustomType(1)).Method("Method2")},
+		{method: New(nil).Method("Add")},
+	} {
+		assert.Equal(t, data.in, data.method.NumIn(), data.method.Name())
+		assert.Equal(t, data.out, data.method.NumOut(), data.method.Name())
+		assert.Equal(t, data.variadic, data.method.IsVariadic(), data.method.Name())
+		if data.method.IsValid() {
+			assert.Equal(t, len(data.method.InTypes()), data.method.NumIn())
+			assert.Equal(t, len(data.method.OutTypes()), data.method.NumOut())
+		}
+	}
+}
 
-	assert.Equal(t, len(method.InTypes()), 0)
-	assert.Equal(t, len(method.OutTypes()), 0)
+type MyError struct {
+	Code int
 }
 
-func TestMethodsValidityOnPtr(t *testing.T) {
-	t.Parallel()
-	ct := CustomType(1)
-	obj := New(&ct)
+func (me *MyError) Error() string { return fmt.Sprintf("my error %d", me.Code) }
 
-	assert.True(t, obj.IsPtr())
+type WithCustomErrors struct{}
 
-	assert.True(t, obj.Method("Method1").IsValid())
-	assert.True(t, obj.Method("Method2").IsValid())
+func (WithCustomErrors) Fail(code int) (int, *MyError) {
+	if code == 0 {
+		return 1, nil
+	}
+	return 0, &MyError{Code: code}
+}
 
+func TestCallMethodWithCustomErrorType(t *testing.T) {
+	t.Parallel()
+	obj := New(WithCustomErrors{})
 	{
-		res, err := obj.Method("Method1").Call()
+		res, err := obj.Method("Fail").Call(17)
 		assert.Nil(t, err)
-		assert.Equal(t, res.Result, []interface{}{"yep"})
+		assert.True(t, res.IsError())
+		assert.Equal(t, "my error 17", res.Error.Error())
+		var myErr *MyError
+		assert.True(t, errors.As(res.Error, &myErr))
+		assert.Equal(t, 17, myErr.Code)
 	}
 	{
-		res, err := obj.Method("Method2").Call()
+		// A nil *MyError is not an error:
+		res, err := obj.Method("Fail").Call(0)
 		assert.Nil(t, err)
-		assert.Equal(t, res.Result, []interface{}{7})
+		assert.False(t, res.IsError())
+		assert.Nil(t, res.Error)
 	}
 }
 
-func TestMethodsValidityOnNonPtr(t *testing.T) {
-	t.Parallel()
-	obj := New(CustomType(1))
-
-	assert.False(t, obj.IsPtr())
+type Flaky struct {
+	failures int
+	calls    int
+}
 
-	assert.True(t, obj.Method("Method1").IsValid())
-	// False because it's not a pointer
-	assert.False(t, obj.Method("Method2").IsValid())
+func (f *Flaky) Do(what string) (string, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return "", fmt.Errorf("failure %d", f.calls)
+	}
+	return "done " + what, nil
+}
 
+func TestCallRetry(t *testing.T) {
+	t.Parallel()
 	{
-		res, err := obj.Method("Method1").Call()
+		f := &Flaky{failures: 2}
+		res, err := New(f).Method("Do").CallRetry(3, time.Millisecond, "it")
 		assert.Nil(t, err)
-		assert.Equal(t, res.Result, []interface{}{"yep"})
+		assert.False(t, res.IsError())
+		assert.Equal(t, []interface{}{"done it", nil}, res.Result)
+		assert.Equal(t, 3, f.calls)
 	}
 	{
-		_, err := obj.Method("Method2").Call()
+		f := &Flaky{failures: 5}
+		res, err := New(f).Method("Do").CallRetry(3, time.Millisecond, "it")
+		assert.Nil(t, err)
+		assert.True(t, res.IsError())
+		assert.Equal(t, "failure 3", res.Error.Error())
+		assert.Equal(t, 3, f.calls)
+	}
+	{
+		f := &Flaky{failures: 5}
+		res, err := New(f).Method("Do").CallRetry(0, time.Millisecond, "it")
+		assert.Nil(t, err)
+		assert.True(t, res.IsError())
+		assert.Equal(t, 1, f.calls)
+	}
+	{
+		// Not retried:
+		res, err := New(&Flaky{}).Method("Invalid").CallRetry(3, time.Millisecond)
 		assert.NotNil(t, err)
+		assert.Nil(t, res)
 	}
 }
 
-func testCallMethod(t *testing.T, callValue bool, lenResult int) bool {
-	obj := New(&Person{})
-	res, err := obj.Method("ReturnsError").Call(callValue)
-	assert.Nil(t, err)
-	assert.Equal(t, len(res.Result), lenResult)
-	return res.IsError()
-}
-
-func TestCallMethodWithoutErrResult(t *testing.T) {
-	t.Parallel()
-	isErr := testCallMethod(t, true, 3)
-	assert.True(t, isErr)
-}
-
-func TestCallMethodWithErrResult(t *testing.T) {
-	t.Parallel()
-	isErr := testCallMethod(t, false, 3)
-	assert.False(t, isErr)
-}
-
 func TestTag(t *testing.T) {
 	t.Parallel()
 	obj := New(&Person{})
@@ -466,6 +1562,33 @@ func TestAllTags(t *testing.T) {
 	tagsStr, err := fld.TagsString()
 	assert.Nil(t, err)
 	assert.Equal(t, `tag:"be" tag2:"1,2,3"`, tagsStr)
+
+	_, err = New(Address{}).Field("Invalid").TagsString()
+	assert.NotNil(t, err)
+}
+
+func TestInTagSet(t *testing.T) {
+	t.Parallel()
+	s := struct {
+		Color string `oneof:"red, green,blue"`
+		Size  int    `oneof:"1,2,3"`
+	}{Color: "green", Size: 4}
+	obj := New(s)
+
+	in, err := obj.Field("Color").InTagSet("oneof")
+	assert.Nil(t, err)
+	assert.True(t, in)
+
+	in, err = obj.Field("Size").InTagSet("oneof")
+	assert.Nil(t, err)
+	assert.False(t, in)
+
+	in, err = obj.Field("Size").InTagSet("invalid")
+	assert.Nil(t, err)
+	assert.False(t, in)
+
+	_, err = obj.Field("Invalid").InTagSet("oneof")
+	assert.NotNil(t, err)
 }
 
 func TestNewFromType(t *testing.T) {
@@ -473,9 +1596,25 @@ func TestNewFromType(t *testing.T) {
 	obj1 := NewFromType(reflect.TypeOf(Person{}))
 	obj2 := New(&Person{})
 
-	assert.Equal(t, obj1.objType.String(), obj2.objType.String())
-	assert.Equal(t, obj1.objKind.String(), obj2.objKind.String())
-	assert.Equal(t, obj1.underlyingType.String(), obj2.underlyingType.String())
+	assert.Equal(t, obj1.Type(), obj2.Type())
+	assert.Equal(t, obj1.Kind(), obj2.Kind())
+	assert.Equal(t, obj1.UnderlyingType(), obj2.UnderlyingType())
+	assert.Equal(t, reflect.TypeOf(Person{}), obj1.UnderlyingType())
+	assert.Equal(t, reflect.Ptr, obj1.Kind())
+}
+
+func TestTypeAccessors(t *testing.T) {
+	t.Parallel()
+	for _, ty := range []reflect.Type{reflect.TypeOf(1), reflect.TypeOf(""), reflect.TypeOf([]Person{}), reflect.TypeOf(&Person{})} {
+		assert.Equal(t, ty, NewFromType(ty).UnderlyingType())
+	}
+	assert.Equal(t, reflect.TypeOf(1), New(1).Type())
+	assert.Equal(t, reflect.TypeOf(1), New(1).UnderlyingType())
+	assert.Equal(t, reflect.Int, New(1).Kind())
+	assert.Equal(t, reflect.Struct, New(Person{}).Kind())
+	assert.Nil(t, New(nil).Type())
+	assert.Nil(t, New(nil).UnderlyingType())
+	assert.Equal(t, reflect.Invalid, New(nil).Kind())
 }
 
 func TestAnonymousFields(t *testing.T) {
@@ -561,6 +1700,78 @@ func TestInnerStruct(t *testing.T) {
 	assert.Equal(t, 2, len(obj.FieldsFlattened()))
 }
 
+func TestIsTime(t *testing.T) {
+	t.Parallel()
+	s := struct {
+		Time    time.Time
+		TimePtr *time.Time
+		Other   time.Duration
+	}{}
+	obj := New(s)
+	assert.True(t, obj.Field("Time").IsTime())
+	assert.True(t, obj.Field("TimePtr").IsTime())
+	assert.False(t, obj.Field("Other").IsTime())
+	assert.False(t, obj.Field("Invalid").IsTime())
+	assert.True(t, New(&Shape{}).Field("Created").IsTime())
+}
+
+type WithInterfaces struct {
+	Writer io.Writer
+	Any    interface{}
+	Name   string
+}
+
+func TestDynamicType(t *testing.T) {
+	t.Parallel()
+	obj := New(WithInterfaces{Writer: &bytes.Buffer{}, Name: "aaa"})
+
+	ty, err := obj.Field("Writer").DynamicType()
+	assert.Nil(t, err)
+	assert.Equal(t, reflect.TypeOf(&bytes.Buffer{}), ty)
+	assert.Equal(t, "io.Writer", obj.Field("Writer").Type().String())
+
+	ty, err = obj.Field("Any").DynamicType()
+	assert.Nil(t, err)
+	assert.Nil(t, ty)
+
+	ty, err = obj.Field("Name").DynamicType()
+	assert.Nil(t, err)
+	assert.Equal(t, reflect.TypeOf(""), ty)
+
+	_, err = obj.Field("Invalid").DynamicType()
+	assert.NotNil(t, err)
+}
+
+func TestSetInterfaceFields(t *testing.T) {
+	t.Parallel()
+	var wi WithInterfaces
+	obj := New(&wi)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, obj.Field("Writer").Set(buf))
+	assert.Equal(t, buf, wi.Writer)
+
+	_, err := wi.Writer.Write([]byte("aaa"))
+	assert.Nil(t, err)
+	assert.Equal(t, "aaa", buf.String())
+
+	// bytes.Buffer (not a pointer) doesn't implement io.Writer:
+	err = obj.Field("Writer").Set(bytes.Buffer{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot use bytes.Buffer as io.Writer")
+
+	assert.Nil(t, obj.Field("Any").Set(17))
+	assert.Equal(t, 17, wi.Any)
+
+	assert.Nil(t, obj.Field("Writer").Set(nil))
+	assert.Nil(t, wi.Writer)
+	assert.Nil(t, obj.Field("Any").Set(nil))
+	assert.Nil(t, wi.Any)
+
+	assert.NotNil(t, obj.Field("Name").Set(nil))
+	assert.NotNil(t, obj.Field("Name").Set(17))
+}
+
 func TestExportedUnexported(t *testing.T) {
 	t.Parallel()
 	obj := New(&tmp.TestStruct{})
@@ -573,6 +1784,9 @@ func TestExportedUnexported(t *testing.T) {
 	assert.Equal(t, "unexported", obj.Fields()[2].Name())
 	assert.False(t, obj.Fields()[2].IsExported())
 
+	assert.Equal(t, "github.com/tkrajina/go-reflector/reflector/tmp", obj.Field("unexported").PkgPath())
+	assert.Equal(t, "", obj.Field("Exported").PkgPath())
+
 	err := obj.Field("Exported").Set("aaa")
 	assert.Nil(t, err)
 
@@ -707,6 +1921,63 @@ func TestMapLenGetSet(t *testing.T) {
 
 }
 
+func TestKeysAndMapValue(t *testing.T) {
+	t.Parallel()
+	m := map[string]Address{"home": {Street: "Main", Number: 1}}
+	obj := New(&m)
+	assert.True(t, obj.IsMap())
+
+	keys, err := obj.Keys()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"home"}, keys)
+
+	value, err := obj.MapValue("home")
+	assert.Nil(t, err)
+	street, err := value.Field("Street").Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "Main", street)
+
+	_, err = obj.MapValue("work")
+	assert.NotNil(t, err)
+	assert.Equal(t, "key work not found", err.Error())
+
+	_, err = obj.MapValue(1)
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid key: cannot use int as string", err.Error())
+
+	_, err = New(Address{}).MapValue("home")
+	assert.NotNil(t, err)
+	_, err = New(Address{}).Keys()
+	assert.NotNil(t, err)
+}
+
+func TestSortedMapEntries(t *testing.T) {
+	t.Parallel()
+	m := map[string]int{"b": 2, "c": 3, "a": 1}
+	entries, err := New(&m).SortedMapEntries(func(a, b interface{}) bool { return a.(string) < b.(string) })
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(entries))
+	for n, expected := range []string{"a", "b", "c"} {
+		assert.Equal(t, expected, entries[n].Key.iface)
+		assert.Equal(t, n+1, entries[n].Value.iface)
+	}
+
+	entries, err = New(map[int]Address{1: {Street: "x"}, 2: {Street: "y"}}).SortedMapEntries(func(a, b interface{}) bool { return a.(int) > b.(int) })
+	assert.Nil(t, err)
+	street, err := entries[0].Value.Field("Street").Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "y", street)
+
+	entries, err = New(map[string]int{}).SortedMapEntries(func(a, b interface{}) bool { return true })
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	_, err = New([]int{1}).SortedMapEntries(func(a, b interface{}) bool { return true })
+	assert.NotNil(t, err)
+	_, err = New(m).SortedMapEntries(nil)
+	assert.NotNil(t, err)
+}
+
 func TestMapSet(t *testing.T) {
 	m := map[string]interface{}{"jkljk": 8, "11": 13, "12": nil}
 	o := New(&m)
@@ -722,6 +1993,59 @@ func TestSetStringByIndex(t *testing.T) {
 	assert.NotNil(t, o.SetByIndex(0, 'a'))
 }
 
+type Polygon struct {
+	Name    string
+	Corners [2]Point
+	Points  []Point
+}
+
+func TestArrayOfStructsElements(t *testing.T) {
+	t.Parallel()
+	p := Polygon{Corners: [2]Point{{X: 1, Y: 2}, {X: 3, Y: 4}}, Points: []Point{{X: 5, Y: 6}}}
+
+	corners, err := New(&p).Field("Corners").AsObj()
+	assert.Nil(t, err)
+	assert.True(t, corners.IsSettableByIndex())
+	assert.True(t, New(&p.Points).IsSettableByIndex())
+	assert.False(t, New(p).IsSettableByIndex())
+	assert.False(t, New(map[string]int{}).IsSettableByIndex())
+	for n := 0; n < corners.Len(); n++ {
+		corner, err := corners.Index(n)
+		assert.Nil(t, err)
+		x, err := corner.Field("X").Get()
+		assert.Nil(t, err)
+		assert.Nil(t, corner.Field("X").Set(x.(int)*10))
+	}
+	assert.Equal(t, [2]Point{{X: 10, Y: 2}, {X: 30, Y: 4}}, p.Corners)
+
+	_, err = corners.Index(2)
+	assert.NotNil(t, err)
+	_, err = corners.Index(-1)
+	assert.NotNil(t, err)
+
+	// Slice elements are always addressable:
+	points, err := New(p).Field("Points").AsObj()
+	assert.Nil(t, err)
+	point, err := points.Index(0)
+	assert.Nil(t, err)
+	assert.Nil(t, point.Field("Y").Set(60))
+	assert.Equal(t, []Point{{X: 5, Y: 60}}, p.Points)
+
+	// Not a pointer, the array is a copy and not settable:
+	corners, err = New(p).Field("Corners").AsObj()
+	assert.Nil(t, err)
+	corner, err := corners.Index(0)
+	assert.Nil(t, err)
+	assert.NotNil(t, corner.Field("X").Set(100))
+
+	_, err = New(&p).Field("Name").AsObj()
+	assert.Nil(t, err)
+	_, err = New(&p).Field("Invalid").AsObj()
+	assert.NotNil(t, err)
+	_, err = New(p).Index(0)
+	assert.NotNil(t, err)
+}
+
 func TestWalk(t *testing.T) {
 	t.Parallel()
 
@@ -738,3 +2062,136 @@ func TestWalk(t *testing.T) {
 	}{}
 	_ = s
 }
+
+func TestElemAndKeyType(t *testing.T) {
+	t.Parallel()
+	personType := reflect.TypeOf(Person{})
+	for _, value := range []interface{}{[]Person(nil), [2]Person{}, map[string]Person(nil), make(chan Person), &Person{}} {
+		ty, err := New(value).ElemType()
+		assert.Nil(t, err)
+		assert.Equal(t, personType, ty)
+	}
+	ty, err := New(map[int]Person{}).KeyType()
+	assert.Nil(t, err)
+	assert.Equal(t, reflect.TypeOf(1), ty)
+
+	// No value needed:
+	ty, err = NewFromType(reflect.TypeOf([]Person{})).ElemType()
+	assert.Nil(t, err)
+	assert.Equal(t, reflect.TypeOf([]Person{}), ty)
+	elem := NewFromType(personType)
+	assert.Equal(t, reflect.TypeOf(&Person{}), elem.Type())
+
+	_, err = New(Person{}).ElemType()
+	assert.NotNil(t, err)
+	_, err = New([]Person{}).KeyType()
+	assert.NotNil(t, err)
+	_, err = New(nil).ElemType()
+	assert.NotNil(t, err)
+}
+
+// TestConcurrentReads must be run with -race.
+func TestConcurrentReads(t *testing.T) {
+	ClearTypeCache()
+	p := Person{Name: "John"}
+	shared := New(&p)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj := New(&Company{})
+			assert.Equal(t, 4, len(obj.FieldsAll()))
+			assert.Equal(t, 3, len(shared.FieldsFlattened()))
+			assert.Equal(t, 4, len(shared.Methods()))
+			assert.True(t, shared.Method("Hi").IsValid())
+			value, err := shared.Field("Name").Get()
+			assert.Nil(t, err)
+			assert.Equal(t, "John", value)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNewFromTypeCache(t *testing.T) {
+	ClearTypeCache()
+	assert.Equal(t, 0, cachedTypesCount())
+
+	ty := reflect.TypeOf(Person{})
+	obj1 := NewFromType(ty)
+	obj2 := NewFromType(ty)
+	assert.Equal(t, obj1.ObjMetadata, obj2.ObjMetadata)
+
+	_, found := metadataCache.Load(reflect.PtrTo(ty))
+	assert.True(t, found)
+	assert.Equal(t, 1, cachedTypesCount())
+
+	// New objects don't share the value:
+	assert.Nil(t, obj1.Field("Name").Set("aaa"))
+	name, err := obj2.Field("Name").Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "", name)
+}
+
+func cachedTypesCount() int {
+	var count int
+	metadataCache.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func TestTypeCacheConcurrent(t *testing.T) {
+	t.Parallel()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj := New(&Company{})
+			assert.Equal(t, []string{"Number"}, obj.FindDoubleFields())
+			assert.Equal(t, 3, len(obj.FieldsFlattened()))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCallEachElement(t *testing.T) {
+	t.Parallel()
+	{
+		people := []Person{{Name: "A"}, {Name: "B"}}
+		res, err := New(people).CallEachElement("Hi", "John")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, []interface{}{"Hi John my name is A"}, res[0].Result)
+		assert.Equal(t, []interface{}{"Hi John my name is B"}, res[1].Result)
+	}
+	{
+		// Slice elements are addressable, so pointer receiver methods work:
+		res, err := New([]CustomType{1, 2}).CallEachElement("Method2")
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{7}, res[1].Result)
+	}
+	{
+		// Array elements are addressable only behind a pointer:
+		arr := [2]CustomType{1, 2}
+		_, err := New(arr).CallEachElement("Method2")
+		assert.NotNil(t, err)
+		res, err := New(&arr).CallEachElement("Method2")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+	}
+	{
+		res, err := New([]Person{}).CallEachElement("Hi", "John")
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+	}
+	{
+		_, err := New(Person{}).CallEachElement("Hi", "John")
+		assert.NotNil(t, err)
+		_, err = New([]Person{{}}).CallEachElement("Invalid")
+		assert.NotNil(t, err)
+	}
+}
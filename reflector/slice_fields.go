@@ -0,0 +1,115 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+func (of *ObjField) assertSettableSlice() error {
+	if err := of.assertValid(); err != nil {
+		return err
+	}
+	if of.fieldKind != reflect.Slice {
+		return fmt.Errorf("field %s is not a slice but %s", of.name, of.fieldType.String())
+	}
+	if !of.IsSettable() {
+		return fmt.Errorf("field %s in %T not settable", of.name, of.obj.iface)
+	}
+	return nil
+}
+
+// Filter rebuilds a slice field, retaining only elements for which keep returns true.
+// Works only for settable fields (i.e. the object must be a pointer).
+func (of *ObjField) Filter(keep func(elem interface{}) bool) error {
+	if err := of.assertSettableSlice(); err != nil {
+		return err
+	}
+	if of.value.IsNil() {
+		return nil
+	}
+
+	res := reflect.MakeSlice(of.fieldType, 0, of.value.Len())
+	for i := 0; i < of.value.Len(); i++ {
+		elem := of.value.Index(i)
+		if keep(elem.Interface()) {
+			res = reflect.Append(res, elem)
+		}
+	}
+	of.value.Set(res)
+
+	return nil
+}
+
+// MapElements replaces every element of a slice field with the result of transform.
+// Returned values must be assignable to the slice element type, otherwise the field is left unchanged.
+// Works only for settable fields (i.e. the object must be a pointer).
+func (of *ObjField) MapElements(transform func(elem interface{}) interface{}) error {
+	if err := of.assertSettableSlice(); err != nil {
+		return err
+	}
+	if of.value.IsNil() {
+		return nil
+	}
+
+	elemType := of.fieldType.Elem()
+	res := reflect.MakeSlice(of.fieldType, of.value.Len(), of.value.Len())
+	for i := 0; i < of.value.Len(); i++ {
+		v, err := assignableValue(transform(of.value.Index(i).Interface()), elemType)
+		if err != nil {
+			return fmt.Errorf("element %d of field %s: %w", i, of.name, err)
+		}
+		res.Index(i).Set(v)
+	}
+	of.value.Set(res)
+
+	return nil
+}
+
+// SortBy sorts a slice of structs (or pointers to structs) by the value of the subField in every element.
+// The sort is stable, nil pointer elements (and sub-fields declared in nil embedded pointers) are compared as nil
+// values.
+// Works only for settable fields (i.e. the object must be a pointer).
+func (of *ObjField) SortBy(subField string, less func(a, b interface{}) bool) error {
+	if err := of.assertSettableSlice(); err != nil {
+		return err
+	}
+
+	elemType := of.fieldType.Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("field %s is not a slice of structs but %s", of.name, of.fieldType.String())
+	}
+	structField, found := elemType.FieldByName(subField)
+	if !found {
+		return fmt.Errorf("invalid field %s in %s", subField, elemType.String())
+	}
+	if structField.PkgPath != "" {
+		return fmt.Errorf("cannot sort by unexported field %s.%s", elemType.String(), subField)
+	}
+
+	slice := of.value
+	key := func(n int) interface{} {
+		elem := slice.Index(n)
+		if isPtr {
+			if elem.IsNil() {
+				return nil
+			}
+			elem = elem.Elem()
+		}
+		// Promoted through a nil embedded pointer:
+		value, err := elem.FieldByIndexErr(structField.Index)
+		if err != nil {
+			return nil
+		}
+		return value.Interface()
+	}
+	sort.SliceStable(slice.Interface(), func(i, j int) bool {
+		return less(key(i), key(j))
+	})
+
+	return nil
+}
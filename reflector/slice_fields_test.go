@@ -0,0 +1,142 @@
+package reflector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Table struct {
+	Name    string
+	Numbers []int
+	Rows    []Address
+	PtrRows []*Address
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	table := Table{Numbers: []int{1, 2, 3, 4, 5, 6}}
+	obj := New(&table)
+
+	err := obj.Field("Numbers").Filter(func(elem interface{}) bool { return elem.(int)%2 == 0 })
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 4, 6}, table.Numbers)
+
+	err = obj.Field("Numbers").Filter(func(elem interface{}) bool { return false })
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, table.Numbers)
+
+	// Nil slices stay nil:
+	err = obj.Field("Rows").Filter(func(elem interface{}) bool { return true })
+	assert.Nil(t, err)
+	assert.Nil(t, table.Rows)
+}
+
+func TestFilterInvalid(t *testing.T) {
+	t.Parallel()
+	table := Table{Numbers: []int{1, 2, 3}}
+	keepAll := func(elem interface{}) bool { return true }
+
+	assert.NotNil(t, New(&table).Field("Name").Filter(keepAll))
+	assert.NotNil(t, New(&table).Field("Invalid").Filter(keepAll))
+
+	err := New(table).Field("Numbers").Filter(keepAll)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not settable")
+}
+
+func TestMapElements(t *testing.T) {
+	t.Parallel()
+	table := Table{
+		Numbers: []int{1, 2, 3},
+		PtrRows: []*Address{{Street: "a"}, {Street: "b"}},
+	}
+	obj := New(&table)
+
+	err := obj.Field("Numbers").MapElements(func(elem interface{}) interface{} { return elem.(int) * 10 })
+	assert.Nil(t, err)
+	assert.Equal(t, []int{10, 20, 30}, table.Numbers)
+
+	err = obj.Field("PtrRows").MapElements(func(elem interface{}) interface{} {
+		if elem.(*Address).Street == "a" {
+			return nil
+		}
+		return elem
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(table.PtrRows))
+	assert.Nil(t, table.PtrRows[0])
+	assert.Equal(t, "b", table.PtrRows[1].Street)
+}
+
+func TestMapElementsInvalidType(t *testing.T) {
+	t.Parallel()
+	table := Table{Numbers: []int{1, 2, 3}}
+	obj := New(&table)
+
+	err := obj.Field("Numbers").MapElements(func(elem interface{}) interface{} {
+		if elem.(int) == 2 {
+			return "two"
+		}
+		return elem
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "element 1")
+	assert.Equal(t, []int{1, 2, 3}, table.Numbers)
+
+	err = obj.Field("Numbers").MapElements(func(elem interface{}) interface{} { return nil })
+	assert.NotNil(t, err)
+
+	assert.NotNil(t, New(table).Field("Numbers").MapElements(func(elem interface{}) interface{} { return elem }))
+}
+
+func TestSortBy(t *testing.T) {
+	t.Parallel()
+	table := Table{
+		Rows:    []Address{{Street: "b", Number: 2}, {Street: "c", Number: 1}, {Street: "a", Number: 3}},
+		PtrRows: []*Address{{Street: "b", Number: 2}, {Street: "c", Number: 1}, {Street: "a", Number: 3}},
+	}
+	obj := New(&table)
+
+	byString := func(a, b interface{}) bool { return a.(string) < b.(string) }
+	byInt := func(a, b interface{}) bool { return a.(int) < b.(int) }
+
+	assert.Nil(t, obj.Field("Rows").SortBy("Street", byString))
+	assert.Equal(t, []Address{{Street: "a", Number: 3}, {Street: "b", Number: 2}, {Street: "c", Number: 1}}, table.Rows)
+
+	assert.Nil(t, obj.Field("Rows").SortBy("Number", byInt))
+	assert.Equal(t, []Address{{Street: "c", Number: 1}, {Street: "b", Number: 2}, {Street: "a", Number: 3}}, table.Rows)
+
+	assert.Nil(t, obj.Field("PtrRows").SortBy("Street", byString))
+	assert.Equal(t, "a", table.PtrRows[0].Street)
+	assert.Equal(t, "b", table.PtrRows[1].Street)
+	assert.Equal(t, "c", table.PtrRows[2].Street)
+}
+
+func TestSortByNilEmbeddedPtr(t *testing.T) {
+	t.Parallel()
+	var s struct{ Rows []WithAddressPtr }
+	s.Rows = []WithAddressPtr{{Name: "b", Address: &Address{Street: "b"}}, {Name: "nil"}, {Name: "a", Address: &Address{Street: "a"}}}
+
+	nilFirst := func(a, b interface{}) bool {
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.(string) < b.(string)
+	}
+	assert.Nil(t, New(&s).Field("Rows").SortBy("Street", nilFirst))
+	assert.Equal(t, "nil", s.Rows[0].Name)
+	assert.Equal(t, "a", s.Rows[1].Name)
+	assert.Equal(t, "b", s.Rows[2].Name)
+}
+
+func TestSortByInvalid(t *testing.T) {
+	t.Parallel()
+	table := Table{Numbers: []int{2, 1}, Rows: []Address{{}}}
+	less := func(a, b interface{}) bool { return false }
+
+	assert.NotNil(t, New(&table).Field("Numbers").SortBy("Street", less))
+	assert.NotNil(t, New(&table).Field("Rows").SortBy("Invalid", less))
+	assert.NotNil(t, New(&table).Field("Name").SortBy("Street", less))
+	assert.NotNil(t, New(table).Field("Rows").SortBy("Street", less))
+}
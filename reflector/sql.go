@@ -0,0 +1,101 @@
+package reflector
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// scanner is implemented by types which can be set from database values (like sql.Scanner).
+type scanner interface {
+	Scan(src interface{}) error
+}
+
+var (
+	scannerType = reflect.TypeOf((*scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// SetViaScanner sets the field by calling its Scan method (like database/sql does for sql.Scanner types,
+// for example sql.NullString). The field must be settable. A nil pointer field (with a Scan method on the
+// pointer type) is allocated before scanning.
+func (of *ObjField) SetViaScanner(value interface{}) error {
+	if err := of.assertValid(); err != nil {
+		return err
+	}
+	if !of.IsSettable() {
+		return fmt.Errorf("field %s in %T not settable", of.name, of.obj.iface)
+	}
+
+	v := of.value
+	if v.Kind() != reflect.Ptr || !v.Type().Implements(scannerType) {
+		v = v.Addr()
+	}
+	if !v.Type().Implements(scannerType) {
+		return fmt.Errorf("field %s in %T (%s) doesn't implement Scan", of.name, of.obj.iface, of.fieldType.String())
+	}
+
+	defer of.obj.lock()()
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return v.Interface().(scanner).Scan(value)
+}
+
+// GetViaValuer returns the result of the field's Value method (see driver.Valuer), so the value is the same
+// as the one database/sql would send to the driver. A nil pointer field returns nil.
+func (of *ObjField) GetViaValuer() (interface{}, error) {
+	if err := of.assertValid(); err != nil {
+		return nil, err
+	}
+	if !of.IsExported() {
+		return nil, fmt.Errorf("cannot read unexported field %T.%s", of.obj.iface, of.name)
+	}
+
+	defer of.obj.rlock()()
+	v := of.value
+	if !v.Type().Implements(valuerType) && v.CanAddr() {
+		v = v.Addr()
+	}
+	if !v.Type().Implements(valuerType) {
+		return nil, fmt.Errorf("field %s in %T (%s) doesn't implement Value", of.name, of.obj.iface, of.fieldType.String())
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+	return v.Interface().(driver.Valuer).Value()
+}
+
+// ColumnDef describes a database column mapped to a struct field (see Obj.Columns).
+type ColumnDef struct {
+	// Column is the column name, from the tag (or the field name if the tag has no name).
+	Column string
+	// Field is the Go field name.
+	Field      string
+	Kind       reflect.Kind
+	PrimaryKey bool
+}
+
+// Columns returns column definitions for exported flattened fields, with column names from the tag, for
+// example `db:"user_id,pk"`. The "pk" tag option marks primary key columns, fields tagged with "-" are skipped.
+func (o *Obj) Columns(tagName string) []ColumnDef {
+	var res []ColumnDef
+	for _, field := range o.FieldsFlattened() {
+		if !field.IsValid() || !field.IsExported() {
+			continue
+		}
+		column, skip := tagKey(field.structField, tagName)
+		if skip {
+			continue
+		}
+		var pk bool
+		for _, option := range strings.Split(field.structField.Tag.Get(tagName), ",")[1:] {
+			if strings.TrimSpace(option) == "pk" {
+				pk = true
+			}
+		}
+		res = append(res, ColumnDef{Column: column, Field: field.name, Kind: field.fieldKind, PrimaryKey: pk})
+	}
+	return res
+}
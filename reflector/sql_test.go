@@ -0,0 +1,131 @@
+package reflector
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Row struct {
+	Name    sql.NullString
+	Count   *sql.NullInt64
+	Plain   string
+	private sql.NullString
+}
+
+func TestSetViaScanner(t *testing.T) {
+	t.Parallel()
+	var r Row
+	obj := New(&r)
+
+	assert.Nil(t, obj.Field("Name").SetViaScanner("John"))
+	assert.Equal(t, sql.NullString{String: "John", Valid: true}, r.Name)
+	assert.Nil(t, obj.Field("Name").SetViaScanner(nil))
+	assert.Equal(t, sql.NullString{}, r.Name)
+
+	assert.Nil(t, obj.Field("Count").SetViaScanner(int64(17)))
+	assert.Equal(t, &sql.NullInt64{Int64: 17, Valid: true}, r.Count)
+
+	err := obj.Field("Count").SetViaScanner("not a number")
+	assert.NotNil(t, err)
+
+	err = obj.Field("Plain").SetViaScanner("aaa")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "doesn't implement Scan")
+
+	assert.NotNil(t, New(r).Field("Name").SetViaScanner("John"))
+	assert.NotNil(t, obj.Field("private").SetViaScanner("John"))
+	assert.NotNil(t, obj.Field("Invalid").SetViaScanner("John"))
+}
+
+type PtrValuer struct{ Fail bool }
+
+func (pv *PtrValuer) Value() (driver.Value, error) {
+	if pv.Fail {
+		return nil, errors.New("failed")
+	}
+	return "ptr", nil
+}
+
+type WithValuers struct {
+	Name    sql.NullString
+	Count   *sql.NullInt64
+	Ptr     PtrValuer
+	Plain   string
+	private sql.NullString
+}
+
+func TestGetViaValuer(t *testing.T) {
+	t.Parallel()
+	w := WithValuers{Name: sql.NullString{String: "John", Valid: true}}
+	obj := New(&w)
+
+	value, err := obj.Field("Name").GetViaValuer()
+	assert.Nil(t, err)
+	assert.Equal(t, "John", value)
+
+	value, err = obj.Field("Count").GetViaValuer()
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	w.Count = &sql.NullInt64{Int64: 17, Valid: true}
+	value, err = obj.Field("Count").GetViaValuer()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(17), value)
+
+	value, err = obj.Field("Ptr").GetViaValuer()
+	assert.Nil(t, err)
+	assert.Equal(t, "ptr", value)
+	w.Ptr.Fail = true
+	_, err = obj.Field("Ptr").GetViaValuer()
+	assert.NotNil(t, err)
+
+	// Not addressable, Value has a pointer receiver:
+	_, err = New(w).Field("Ptr").GetViaValuer()
+	assert.NotNil(t, err)
+
+	value, err = New(w).Field("Name").GetViaValuer()
+	assert.Nil(t, err)
+	assert.Equal(t, "John", value)
+
+	_, err = obj.Field("Plain").GetViaValuer()
+	assert.NotNil(t, err)
+	_, err = obj.Field("private").GetViaValuer()
+	assert.NotNil(t, err)
+	_, err = obj.Field("Invalid").GetViaValuer()
+	assert.NotNil(t, err)
+}
+
+type Timestamps struct {
+	Created int64 `db:"created_at"`
+	Updated int64 `db:"updated_at"`
+}
+
+type UserRow struct {
+	ID    int64  `db:"id,pk"`
+	Email string `db:"email, unique"`
+	Name  string
+	Temp  string `db:"-"`
+	Timestamps
+	Nick    sql.NullString `db:"nick"`
+	private string
+}
+
+func TestColumns(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []ColumnDef{
+		{Column: "id", Field: "ID", Kind: reflect.Int64, PrimaryKey: true},
+		{Column: "email", Field: "Email", Kind: reflect.String},
+		{Column: "Name", Field: "Name", Kind: reflect.String},
+		{Column: "created_at", Field: "Created", Kind: reflect.Int64},
+		{Column: "updated_at", Field: "Updated", Kind: reflect.Int64},
+		{Column: "nick", Field: "Nick", Kind: reflect.Struct},
+	}, New(&UserRow{}).Columns("db"))
+
+	assert.Empty(t, New(1).Columns("db"))
+	assert.Empty(t, New((*UserRow)(nil)).Columns("db"))
+}
@@ -0,0 +1,149 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// LeafKinds returns kinds of all leaf types found by walking the object's type recursively through struct fields
+// and through pointer, slice, array and map (key and element) types. Leaves are all types of other kinds, and
+// structs without exported fields (like time.Time, reported as reflect.Struct). Unexported fields are skipped.
+func (o *Obj) LeafKinds() map[reflect.Kind]bool {
+	res := map[reflect.Kind]bool{}
+	if o.objType != nil {
+		collectLeafKinds(o.objType, res, map[reflect.Type]bool{})
+	}
+	return res
+}
+
+func collectLeafKinds(ty reflect.Type, res map[reflect.Kind]bool, visited map[reflect.Type]bool) {
+	if visited[ty] {
+		return
+	}
+	visited[ty] = true
+
+	switch ty.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		collectLeafKinds(ty.Elem(), res, visited)
+	case reflect.Map:
+		collectLeafKinds(ty.Key(), res, visited)
+		collectLeafKinds(ty.Elem(), res, visited)
+	case reflect.Struct:
+		if !hasExportedFields(ty) {
+			res[reflect.Struct] = true
+			return
+		}
+		for i := 0; i < ty.NumField(); i++ {
+			if ty.Field(i).PkgPath == "" {
+				collectLeafKinds(ty.Field(i).Type, res, visited)
+			}
+		}
+	default:
+		res[ty.Kind()] = true
+	}
+}
+
+// ToDOT returns a Graphviz DOT representation of the object's type graph. Nodes are types, and every struct
+// field is an edge (labeled with the field name) from the struct to the field's type. Pointer, slice, array and
+// map types are followed to their element types, structs without exported fields (like time.Time) are leaves.
+func (o *Obj) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph {\n")
+	if o.underlyingType != nil {
+		g := dotGraph{b: &b, visited: map[reflect.Type]bool{}}
+		g.writeType(o.underlyingType)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+type dotGraph struct {
+	b       *strings.Builder
+	visited map[reflect.Type]bool
+}
+
+func (g *dotGraph) writeType(ty reflect.Type) {
+	ty = dotNodeType(ty)
+	if g.visited[ty] {
+		return
+	}
+	g.visited[ty] = true
+
+	fmt.Fprintf(g.b, "\t%q;\n", ty.String())
+	if ty.Kind() != reflect.Struct || !hasExportedFields(ty) {
+		return
+	}
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		fmt.Fprintf(g.b, "\t%q -> %q [label=%q];\n", ty.String(), dotNodeType(field.Type).String(), field.Name)
+		g.writeType(field.Type)
+	}
+}
+
+// dotNodeType returns the type represented by a node, i.e. the type with pointers, slices, arrays and maps
+// removed.
+func dotNodeType(ty reflect.Type) reflect.Type {
+	for {
+		switch ty.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			ty = ty.Elem()
+		default:
+			return ty
+		}
+	}
+}
+
+// Stats contains aggregated information about a type's shape (see Obj.TypeStats).
+type Stats struct {
+	// LeafFields is the number of fields which are not (pointers to, slices of, or maps of) nested structs.
+	LeafFields int
+	// MaxDepth is the deepest field nesting level, fields of the root struct are at level 1.
+	MaxDepth int
+	Slices   int
+	Maps     int
+	Pointers int
+}
+
+// TypeStats walks the type graph (through nested structs, pointers, slices, arrays and map values) and returns
+// aggregated stats. Structs without exported fields (like time.Time) are leaves, and so are recursive
+// references to a struct already being walked. Pointers to structs are described by their struct type.
+func (o *Obj) TypeStats() Stats {
+	var res Stats
+	if o.underlyingType != nil {
+		res.collect(o.underlyingType, 0, map[reflect.Type]bool{})
+	}
+	return res
+}
+
+// collect adds stats for a value of the type at the given depth, and returns true if the type is a leaf.
+func (s *Stats) collect(ty reflect.Type, depth int, walking map[reflect.Type]bool) bool {
+	for {
+		switch ty.Kind() {
+		case reflect.Ptr:
+			s.Pointers++
+		case reflect.Slice:
+			s.Slices++
+		case reflect.Map:
+			s.Maps++
+		case reflect.Array:
+		default:
+			if ty.Kind() != reflect.Struct || !hasExportedFields(ty) || walking[ty] {
+				return true
+			}
+			walking[ty] = true
+			defer delete(walking, ty)
+
+			for i := 0; i < ty.NumField(); i++ {
+				if s.collect(ty.Field(i).Type, depth+1, walking) {
+					s.LeafFields++
+					if depth+1 > s.MaxDepth {
+						s.MaxDepth = depth + 1
+					}
+				}
+			}
+			return false
+		}
+		ty = ty.Elem()
+	}
+}
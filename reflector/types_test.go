@@ -0,0 +1,81 @@
+package reflector
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeafKinds(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, map[reflect.Kind]bool{reflect.String: true, reflect.Int: true}, New(&Person{}).LeafKinds())
+	assert.Equal(t, map[reflect.Kind]bool{reflect.Int: true}, New(1).LeafKinds())
+	assert.Equal(t, map[reflect.Kind]bool{}, New(nil).LeafKinds())
+	assert.Equal(t, map[reflect.Kind]bool{reflect.Struct: true}, New(struct{}{}).LeafKinds())
+
+	kinds := New(Table{}).LeafKinds()
+	assert.Equal(t, map[reflect.Kind]bool{reflect.String: true, reflect.Int: true}, kinds)
+
+	kinds = New(struct {
+		Bytes []byte
+		Next  *Form
+	}{}).LeafKinds()
+	// Form.Shapes contain a time.Time (a leaf struct), unexported fields (like Shape.internal) are skipped:
+	assert.Equal(t, map[reflect.Kind]bool{reflect.Uint8: true, reflect.String: true, reflect.Int: true, reflect.Struct: true}, kinds)
+
+	kinds = New(struct {
+		Created time.Time
+		hidden  float64
+	}{}).LeafKinds()
+	assert.Equal(t, map[reflect.Kind]bool{reflect.Struct: true}, kinds)
+
+	kinds = New(map[float32][]*bool{}).LeafKinds()
+	assert.Equal(t, map[reflect.Kind]bool{reflect.Float32: true, reflect.Bool: true}, kinds)
+
+	kinds = New(WithInterfaces{}).LeafKinds()
+	assert.Equal(t, map[reflect.Kind]bool{reflect.String: true, reflect.Interface: true}, kinds)
+}
+
+func TestToDOT(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, `digraph {
+	"reflector.Table";
+	"reflector.Table" -> "string" [label="Name"];
+	"string";
+	"reflector.Table" -> "int" [label="Numbers"];
+	"int";
+	"reflector.Table" -> "reflector.Address" [label="Rows"];
+	"reflector.Address";
+	"reflector.Address" -> "string" [label="Street"];
+	"reflector.Address" -> "int" [label="Number"];
+	"reflector.Table" -> "reflector.Address" [label="PtrRows"];
+}
+`, New(&Table{}).ToDOT())
+
+	assert.Equal(t, "digraph {\n\t\"int\";\n}\n", New(1).ToDOT())
+	assert.Equal(t, "digraph {\n}\n", New(nil).ToDOT())
+
+	dot := New(Form{}).ToDOT()
+	assert.Contains(t, dot, `"time.Time";`)
+	assert.NotContains(t, dot, `"time.Time" ->`)
+}
+
+func TestTypeStats(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, Stats{LeafFields: 3, MaxDepth: 2}, New(&Person{}).TypeStats())
+	assert.Equal(t, New(Person{}).TypeStats(), New(&Person{}).TypeStats())
+	assert.Equal(t, Stats{LeafFields: 6, MaxDepth: 2, Slices: 3, Pointers: 1}, New(Table{}).TypeStats())
+	assert.Equal(t, Stats{}, New(nil).TypeStats())
+	assert.Equal(t, Stats{}, New(1).TypeStats())
+	assert.Equal(t, Stats{Maps: 1, Slices: 1}, New(map[string][]int{}).TypeStats())
+
+	// Recursive types:
+	type Node struct {
+		Value    int
+		Children []*Node
+		Attrs    map[string]string
+	}
+	assert.Equal(t, Stats{LeafFields: 3, MaxDepth: 1, Slices: 1, Pointers: 1, Maps: 1}, New(Node{}).TypeStats())
+}
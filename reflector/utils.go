@@ -2,7 +2,9 @@ package reflector
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 )
 
 // ParseTag parses a golang struct tag into a map.
@@ -58,3 +60,67 @@ func ParseTag(tag string) (map[string]string, error) {
 
 	return res, nil
 }
+
+// assignableValue returns the value as reflect.Value, but only if it can be assigned to a value of type ty.
+// A nil value is converted to the zero value for types which can be nil.
+func assignableValue(value interface{}, ty reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		switch ty.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+			return reflect.Zero(ty), nil
+		}
+		return reflect.Value{}, fmt.Errorf("cannot use nil as %s", ty.String())
+	}
+	v := reflect.ValueOf(value)
+	if !v.Type().AssignableTo(ty) {
+		return reflect.Value{}, fmt.Errorf("cannot use %s as %s", v.Type().String(), ty.String())
+	}
+	return v, nil
+}
+
+// errorList collects multiple errors and reports them as one.
+type errorList []error
+
+func (el errorList) Error() string {
+	msgs := make([]string, len(el))
+	for n := range el {
+		msgs[n] = el[n].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (el errorList) asError() error {
+	if len(el) == 0 {
+		return nil
+	}
+	return el
+}
+
+// isNil checks if the value is nil or a nil pointer, map, slice, chan, func or interface.
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// tagKey returns the name from a json-style tag (`json:"name,omitempty"`), or the field name if the tag
+// has no name. Skip is true for the "-" tag.
+func tagKey(field reflect.StructField, tagName string) (key string, skip bool) {
+	tag := field.Tag.Get(tagName)
+	if tag == "-" {
+		return "", true
+	}
+	if pos := strings.Index(tag, ","); pos >= 0 {
+		tag = tag[:pos]
+	}
+	if tag == "" {
+		return field.Name, false
+	}
+	return tag, false
+}
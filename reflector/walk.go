@@ -0,0 +1,100 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// leafWalker visits settable leaf values reachable from a value: exported struct fields (recursively, including
+// embedded structs and non nil pointers to structs) and slice/array elements. Structs without exported fields
+// (like time.Time) are leaves.
+type leafWalker struct {
+	fn      func(path string, v reflect.Value) error
+	visited map[uintptr]bool
+}
+
+func walkLeaves(v reflect.Value, fn func(path string, v reflect.Value) error) error {
+	w := &leafWalker{fn: fn, visited: map[uintptr]bool{}}
+	return w.walk("", v)
+}
+
+func (w *leafWalker) walk(path string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			break
+		}
+		if w.visited[v.Pointer()] {
+			return nil
+		}
+		w.visited[v.Pointer()] = true
+		return w.walk(path, v.Elem())
+	case reflect.Struct:
+		if !hasExportedFields(v.Type()) {
+			break
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			if err := w.walk(joinPath(path, field.Name), v.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if isBytes(v.Type()) {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := w.walk(joinPath(path, strconv.Itoa(i)), v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !v.CanSet() {
+		return nil
+	}
+	return w.fn(path, v)
+}
+
+// NormalizeStrings applies fn to every settable string value, including strings in nested structs and
+// string elements of slices and arrays (for example strings.TrimSpace). The object must be a pointer to struct.
+func (o *Obj) NormalizeStrings(fn func(string) string) error {
+	if err := o.assertBindable(); err != nil {
+		return err
+	}
+	return walkLeaves(o.fieldsValue, func(path string, v reflect.Value) error {
+		if v.Kind() == reflect.String {
+			v.SetString(fn(v.String()))
+		}
+		return nil
+	})
+}
+
+// TransformLeaves calls fn for every settable leaf value (see NormalizeStrings for which values are visited),
+// and sets the leaf to the returned value if fn returns true. Paths are dotted field names and indexes, for
+// example "Shapes.0.Name". The object must be a pointer to struct.
+//
+// The walk stops at the first value which can't be set (for example because of a wrong type).
+func (o *Obj) TransformLeaves(fn func(path string, value interface{}) (interface{}, bool)) error {
+	if err := o.assertBindable(); err != nil {
+		return err
+	}
+	return walkLeaves(o.fieldsValue, func(path string, v reflect.Value) error {
+		newValue, changed := fn(path, v.Interface())
+		if !changed {
+			return nil
+		}
+		value, err := assignableValue(newValue, v.Type())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.Set(value)
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package reflector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Form struct {
+	Name     string
+	Label    UpperString
+	Emails   []string
+	Address  Address
+	Previous *Address
+	Shapes   []Shape
+	Bytes    []byte
+	Count    int
+	Next     *Form
+	internal string
+}
+
+func TestNormalizeStrings(t *testing.T) {
+	t.Parallel()
+	form := Form{
+		Name:     "  John ",
+		Label:    " label ",
+		Emails:   []string{" a@b.c", "d@e.f "},
+		Address:  Address{Street: " Main "},
+		Previous: &Address{Street: " Old "},
+		Shapes:   []Shape{{Name: " square "}},
+		Bytes:    []byte(" bytes "),
+		internal: " internal ",
+	}
+	// Cycles are visited only once:
+	form.Next = &form
+
+	assert.Nil(t, New(&form).NormalizeStrings(strings.TrimSpace))
+	assert.Equal(t, "John", form.Name)
+	assert.Equal(t, UpperString("label"), form.Label)
+	assert.Equal(t, []string{"a@b.c", "d@e.f"}, form.Emails)
+	assert.Equal(t, "Main", form.Address.Street)
+	assert.Equal(t, "Old", form.Previous.Street)
+	assert.Equal(t, "square", form.Shapes[0].Name)
+	assert.Equal(t, []byte(" bytes "), form.Bytes)
+	assert.Equal(t, " internal ", form.internal)
+
+	assert.NotNil(t, New(form).NormalizeStrings(strings.TrimSpace))
+}
+
+func TestTransformLeaves(t *testing.T) {
+	t.Parallel()
+	form := Form{
+		Name:     "John",
+		Emails:   []string{"a@b.c"},
+		Previous: &Address{Street: "Old", Number: 7},
+		Shapes:   []Shape{{Name: "square"}},
+		Count:    3,
+	}
+	var paths []string
+	err := New(&form).TransformLeaves(func(path string, value interface{}) (interface{}, bool) {
+		paths = append(paths, path)
+		switch v := value.(type) {
+		case int:
+			return v * 10, true
+		case string:
+			if strings.Contains(v, "@") {
+				return "<redacted>", true
+			}
+		}
+		return nil, false
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"<redacted>"}, form.Emails)
+	assert.Equal(t, 70, form.Previous.Number)
+	assert.Equal(t, 30, form.Count)
+	assert.Equal(t, "John", form.Name)
+	assert.Contains(t, paths, "Shapes.0.Name")
+	assert.Contains(t, paths, "Address.Street")
+	assert.NotContains(t, paths, "internal")
+
+	err = New(&form).TransformLeaves(func(path string, value interface{}) (interface{}, bool) {
+		return "string", path == "Count"
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, "Count: cannot use string as int", err.Error())
+
+	assert.NotNil(t, New(form).TransformLeaves(func(path string, value interface{}) (interface{}, bool) { return nil, false }))
+}
@@ -0,0 +1,155 @@
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+var (
+	zeroFuncs      = map[reflect.Type]func(interface{}) bool{}
+	zeroFuncsMutex sync.RWMutex
+)
+
+// RegisterZeroFunc registers a custom zero detection for values of type t, for example for sql.NullString
+// an "empty" value could be one with Valid==false. Unregistered types use reflect's Value.IsZero.
+// Registering a nil func removes the existing one.
+func RegisterZeroFunc(t reflect.Type, fn func(interface{}) bool) {
+	zeroFuncsMutex.Lock()
+	defer zeroFuncsMutex.Unlock()
+
+	if fn == nil {
+		delete(zeroFuncs, t)
+		return
+	}
+	zeroFuncs[t] = fn
+}
+
+func isZeroValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	if v.CanInterface() {
+		zeroFuncsMutex.RLock()
+		fn, found := zeroFuncs[v.Type()]
+		zeroFuncsMutex.RUnlock()
+		if found {
+			return fn(v.Interface())
+		}
+	}
+	return v.IsZero()
+}
+
+// IsZero checks if the field value is zero (see RegisterZeroFunc for custom zero detection).
+// Invalid fields are always zero.
+func (of *ObjField) IsZero() bool {
+	if !of.IsValid() {
+		return true
+	}
+	defer of.obj.rlock()()
+	return isZeroValue(of.value)
+}
+
+// IsZero checks if the object value is zero (see RegisterZeroFunc for custom zero detection). Pointers are
+// dereferenced, so a pointer to a zero struct is zero. Nil values are zero.
+func (o *Obj) IsZero() bool {
+	defer o.rlock()()
+	return isZeroValue(o.fieldsValue)
+}
+
+// SetZero sets the field to the zero value of its type.
+func (of *ObjField) SetZero() error {
+	if err := of.assertValid(); err != nil {
+		return err
+	}
+	if !of.IsSettable() {
+		return fmt.Errorf("field %s in %T not settable", of.name, of.obj.iface)
+	}
+	defer of.obj.lock()()
+	of.value.Set(reflect.Zero(of.fieldType))
+	return nil
+}
+
+// Reset sets all settable (exported) fields to zero values, unexported fields are left unchanged.
+// The object must be a pointer to struct.
+func (o *Obj) Reset() error {
+	if err := o.assertBindable(); err != nil {
+		return err
+	}
+	defer o.lock()()
+	for i := 0; i < o.fieldsValue.NumField(); i++ {
+		if field := o.fieldsValue.Field(i); field.CanSet() {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+	return nil
+}
+
+// NonZeroFields returns exported flattened fields with non-zero values (see ObjField.IsZero).
+func (o *Obj) NonZeroFields() []ObjField {
+	var res []ObjField
+	for _, field := range o.FieldsFlattened() {
+		if field.IsValid() && field.IsExported() && !field.IsZero() {
+			res = append(res, field)
+		}
+	}
+	return res
+}
+
+// MissingRequired returns paths of required fields with zero values (see IsZero). Fields are required if the
+// tag is true, for example `required:"true"` for tagName "required".
+//
+// Nested structs (and non nil pointers to structs) are checked recursively with dotted paths (for example
+// "Address.Street"), fields of embedded structs are checked as if they were declared in the outer struct.
+// Every pointer is followed only once, so values shared by multiple pointers are reported under the first path.
+func (o *Obj) MissingRequired(tagName string) ([]string, error) {
+	if !o.IsStructOrPtrToStruct() || !o.fieldsValue.IsValid() {
+		return nil, fmt.Errorf("cannot check required fields of %s", o.String())
+	}
+	defer o.rlock()()
+	res := []string{}
+	collectMissingRequired(o.fieldsValue, tagName, "", &res, map[uintptr]bool{})
+	return res, nil
+}
+
+func collectMissingRequired(v reflect.Value, tagName, prefix string, res *[]string, visited map[uintptr]bool) {
+	ty := v.Type()
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		fieldValue := v.Field(i)
+		if field.Anonymous {
+			if embedded := derefStruct(fieldValue, visited); embedded.IsValid() {
+				collectMissingRequired(embedded, tagName, prefix, res, visited)
+			}
+			continue
+		}
+
+		path := prefix + field.Name
+		if required, _ := strconv.ParseBool(field.Tag.Get(tagName)); required && isZeroValue(fieldValue) {
+			*res = append(*res, path)
+		}
+		if nested := derefStruct(fieldValue, visited); nested.IsValid() && hasExportedFields(nested.Type()) {
+			collectMissingRequired(nested, tagName, path+".", res, visited)
+		}
+	}
+}
+
+// derefStruct returns the struct value (directly or behind a non nil pointer not visited before), or an invalid
+// value for everything else.
+func derefStruct(v reflect.Value, visited map[uintptr]bool) reflect.Value {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() || visited[v.Pointer()] {
+			return reflect.Value{}
+		}
+		visited[v.Pointer()] = true
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v
+}
@@ -0,0 +1,159 @@
+package reflector
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type WithNullable struct {
+	Name     sql.NullString
+	Count    int
+	Ptr      *int
+	internal string
+}
+
+func init() {
+	RegisterZeroFunc(reflect.TypeOf(sql.NullString{}), func(value interface{}) bool {
+		return !value.(sql.NullString).Valid
+	})
+}
+
+func TestFieldIsZero(t *testing.T) {
+	t.Parallel()
+	i := 0
+	obj := New(WithNullable{Name: sql.NullString{String: "not empty but invalid"}, Ptr: &i, internal: "a"})
+	assert.True(t, obj.Field("Name").IsZero())
+	assert.True(t, obj.Field("Count").IsZero())
+	assert.False(t, obj.Field("Ptr").IsZero())
+	assert.False(t, obj.Field("internal").IsZero())
+	assert.True(t, obj.Field("Invalid").IsZero())
+
+	obj = New(WithNullable{Name: sql.NullString{Valid: true}, Count: 1})
+	assert.False(t, obj.Field("Name").IsZero())
+	assert.False(t, obj.Field("Count").IsZero())
+	assert.True(t, obj.Field("Ptr").IsZero())
+	assert.True(t, obj.Field("internal").IsZero())
+}
+
+func TestRegisterZeroFunc(t *testing.T) {
+	type Custom struct{ Value int }
+	RegisterZeroFunc(reflect.TypeOf(Custom{}), func(value interface{}) bool { return value.(Custom).Value < 0 })
+	assert.True(t, isZeroValue(reflect.ValueOf(Custom{Value: -1})))
+	assert.False(t, isZeroValue(reflect.ValueOf(Custom{})))
+
+	RegisterZeroFunc(reflect.TypeOf(Custom{}), nil)
+	assert.False(t, isZeroValue(reflect.ValueOf(Custom{Value: -1})))
+	assert.True(t, isZeroValue(reflect.ValueOf(Custom{})))
+}
+
+func TestObjIsZero(t *testing.T) {
+	t.Parallel()
+	assert.True(t, New(Person{}).IsZero())
+	assert.True(t, New(&Person{}).IsZero())
+	assert.False(t, New(&Person{Address: Address{Number: 1}}).IsZero())
+	assert.True(t, New((*Person)(nil)).IsZero())
+	assert.True(t, New(nil).IsZero())
+	assert.True(t, New(0).IsZero())
+	assert.False(t, New(CustomType(1)).IsZero())
+	assert.True(t, New("").IsZero())
+	assert.True(t, New(sql.NullString{String: "invalid"}).IsZero())
+	assert.False(t, New([]int{}).IsZero())
+}
+
+func TestSetZero(t *testing.T) {
+	t.Parallel()
+	i := 1
+	w := WithNullable{Name: sql.NullString{String: "a", Valid: true}, Count: 2, Ptr: &i, internal: "b"}
+	obj := New(&w)
+	assert.Nil(t, obj.Field("Name").SetZero())
+	assert.Equal(t, sql.NullString{}, w.Name)
+	assert.Nil(t, obj.Field("Ptr").SetZero())
+	assert.Nil(t, w.Ptr)
+	assert.Equal(t, 2, w.Count)
+
+	assert.NotNil(t, obj.Field("internal").SetZero())
+	assert.NotNil(t, obj.Field("Invalid").SetZero())
+	assert.NotNil(t, New(w).Field("Count").SetZero())
+}
+
+func TestReset(t *testing.T) {
+	t.Parallel()
+	i := 1
+	w := WithNullable{Name: sql.NullString{String: "a", Valid: true}, Count: 2, Ptr: &i, internal: "b"}
+	assert.Nil(t, New(&w).Reset())
+	assert.Equal(t, WithNullable{internal: "b"}, w)
+	assert.True(t, New(&w).Field("Count").IsZero())
+
+	p := Person{Name: "John", Address: Address{Street: "Main"}}
+	assert.Nil(t, New(&p).Reset())
+	assert.True(t, New(p).IsZero())
+
+	assert.NotNil(t, New(p).Reset())
+	assert.NotNil(t, New((*Person)(nil)).Reset())
+}
+
+func TestNonZeroFields(t *testing.T) {
+	t.Parallel()
+	names := func(fields []ObjField) []string {
+		res := []string{}
+		for _, field := range fields {
+			res = append(res, field.Name())
+		}
+		return res
+	}
+	i := 0
+	assert.Equal(t, []string{"Name", "Number"}, names(New(&Person{Name: "John", Address: Address{Number: 1}}).NonZeroFields()))
+	assert.Equal(t, []string{}, names(New(Person{}).NonZeroFields()))
+	// A pointer to a zero value is not zero, the custom zero func for sql.NullString is used:
+	assert.Equal(t, []string{"Ptr"}, names(New(WithNullable{Ptr: &i, Name: sql.NullString{String: "a"}, internal: "a"}).NonZeroFields()))
+	assert.Equal(t, []string{}, names(New((*Person)(nil)).NonZeroFields()))
+	assert.Equal(t, []string{}, names(New(1).NonZeroFields()))
+}
+
+type DBConfig struct {
+	Host string `required:"true"`
+	Port int    `required:"true"`
+	User string
+}
+
+type ServiceConfig struct {
+	Name    string   `required:"true"`
+	DB      DBConfig `required:"true"`
+	Replica *DBConfig
+	Secret  sql.NullString `required:"1"`
+	WithNullable
+	Parent *ServiceConfig
+}
+
+func TestMissingRequired(t *testing.T) {
+	t.Parallel()
+	{
+		var c ServiceConfig
+		missing, err := New(&c).MissingRequired("required")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Name", "DB", "DB.Host", "DB.Port", "Secret"}, missing)
+	}
+	{
+		c := ServiceConfig{
+			Name:    "svc",
+			DB:      DBConfig{Host: "localhost"},
+			Replica: &DBConfig{Port: 1},
+			Secret:  sql.NullString{String: "invalid"},
+		}
+		c.Parent = &c
+		missing, err := New(c).MissingRequired("required")
+		assert.Nil(t, err)
+		// Every pointer is checked once (Parent.Replica is the already checked Replica):
+		assert.Equal(t, []string{"DB.Port", "Replica.Host", "Secret", "Parent.DB.Port", "Parent.Secret"}, missing)
+	}
+	{
+		missing, err := New(&DBConfig{Host: "h", Port: 1}).MissingRequired("required")
+		assert.Nil(t, err)
+		assert.Empty(t, missing)
+	}
+	_, err := New(1).MissingRequired("required")
+	assert.NotNil(t, err)
+}